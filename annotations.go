@@ -0,0 +1,358 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// annotate applies to the schema the keywords from "openapi" and "validate" tags of the struct field.
+//
+// The "openapi" tag is a comma-separated list of keywords, like
+// `openapi:"description=User ID,format=uuid,minLength=1,enum=a|b|c,readOnly"`.
+// A comma or a pipe inside of a value can be escaped with a backslash.
+//
+// The "validate" tag follows the syntax of github.com/go-playground/validator
+// and only the rules that have an equivalent in JSON Schema are recognized.
+//
+// The returned flag reports if the tags mark the field as required.
+func annotate(s *Schema, f structField) (bool, error) {
+	required := false
+	if tag, ok := f.tag.Lookup("openapi"); ok {
+		for _, item := range splitEscaped(tag, ',') {
+			key, val, _ := strings.Cut(item, "=")
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			if key == "required" {
+				required = true
+				continue
+			}
+			err := applyKeyword(s, key, val)
+			if err != nil {
+				return false, fmt.Errorf("openapi tag: %s: %w", key, err)
+			}
+		}
+	}
+	if tag, ok := f.tag.Lookup("validate"); ok {
+		for _, rule := range strings.Split(tag, ",") {
+			key, val, _ := strings.Cut(rule, "=")
+			if key == "dive" {
+				// The rest of the rules apply to the elements.
+				break
+			}
+			if key == "required" {
+				required = true
+				continue
+			}
+			err := applyRule(s, f.typ, key, val)
+			if err != nil {
+				return false, fmt.Errorf("validate tag: %s: %w", key, err)
+			}
+		}
+	}
+	return required, nil
+}
+
+// applyKeyword sets the schema keyword from the "openapi" tag.
+func applyKeyword(s *Schema, key, val string) error {
+	var err error
+	switch key {
+	case "title":
+		s.Title = val
+	case "description":
+		s.Description = val
+	case "format":
+		s.Format = val
+	case "pattern":
+		s.Pattern = val
+	case "example":
+		s.Example, err = parseTagValue(s, val)
+	case "default":
+		s.Default, err = parseTagValue(s, val)
+	case "const":
+		s.Const, err = parseTagValue(s, val)
+	case "enum":
+		s.Enum = nil
+		for _, item := range splitEscaped(val, '|') {
+			var v any
+			v, err = parseTagValue(s, item)
+			if err != nil {
+				return err
+			}
+			s.Enum = append(s.Enum, v)
+		}
+	case "deprecated":
+		s.Deprecated, err = parseFlag(val)
+	case "readOnly":
+		s.ReadOnly, err = parseFlag(val)
+	case "writeOnly":
+		s.WriteOnly, err = parseFlag(val)
+	case "uniqueItems":
+		s.UniqueItems, err = parseFlag(val)
+	case "minimum":
+		s.Minimum, err = parseNumber(val)
+	case "maximum":
+		s.Maximum, err = parseNumber(val)
+	case "exclusiveMinimum":
+		s.ExclusiveMinimum, err = parseNumber(val)
+	case "exclusiveMaximum":
+		s.ExclusiveMaximum, err = parseNumber(val)
+	case "multipleOf":
+		s.MultipleOf, err = parseNumber(val)
+	case "minLength":
+		s.MinLength, err = strconv.Atoi(val)
+	case "maxLength":
+		s.MaxLength, err = parseInt(val)
+	case "minItems":
+		s.MinItems, err = strconv.Atoi(val)
+	case "maxItems":
+		s.MaxItems, err = parseInt(val)
+	case "minProperties":
+		s.MinProperties, err = strconv.Atoi(val)
+	case "maxProperties":
+		s.MaxProperties, err = parseInt(val)
+	default:
+		return fmt.Errorf("unknown keyword")
+	}
+	return err
+}
+
+// applyRule sets the schema keywords equivalent to the go-playground/validator rule.
+func applyRule(s *Schema, t reflect.Type, key, val string) error {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var err error
+	switch key {
+	case "min", "gte":
+		err = setBound(s, t, val, false, false)
+	case "max", "lte":
+		err = setBound(s, t, val, true, false)
+	case "gt":
+		err = setBound(s, t, val, false, true)
+	case "lt":
+		err = setBound(s, t, val, true, true)
+	case "len":
+		err = setBound(s, t, val, false, false)
+		if err == nil {
+			err = setBound(s, t, val, true, false)
+		}
+	case "oneof":
+		s.Enum = nil
+		for _, item := range splitOneOf(val) {
+			var v any
+			v, err = parseTagValue(s, item)
+			if err != nil {
+				return err
+			}
+			s.Enum = append(s.Enum, v)
+		}
+	case "email", "hostname", "ipv4", "ipv6", "uri", "uuid":
+		s.Format = key
+	case "url":
+		s.Format = "uri"
+	case "datetime":
+		s.Format = "date-time"
+	}
+	// Other rules have no equivalent in JSON Schema and are ignored.
+	return err
+}
+
+// setBound sets the lower or upper bound of the value, length, number of items, or number of properties,
+// depending on the type.
+func setBound(s *Schema, t reflect.Type, val string, upper, exclusive bool) error {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		if exclusive {
+			if upper {
+				n--
+			} else {
+				n++
+			}
+		}
+		switch {
+		case t.Kind() == reflect.String && upper:
+			s.MaxLength = &n
+		case t.Kind() == reflect.String:
+			s.MinLength = n
+		case t.Kind() == reflect.Map && upper:
+			s.MaxProperties = &n
+		case t.Kind() == reflect.Map:
+			s.MinProperties = n
+		case upper:
+			s.MaxItems = &n
+		default:
+			s.MinItems = n
+		}
+	default:
+		n, err := parseNumber(val)
+		if err != nil {
+			return err
+		}
+		switch {
+		case upper && exclusive:
+			s.ExclusiveMaximum = n
+		case upper:
+			s.Maximum = n
+		case exclusive:
+			s.ExclusiveMinimum = n
+		default:
+			s.Minimum = n
+		}
+	}
+	return nil
+}
+
+// parseTagValue converts the value from a struct tag into a value of the type described by the schema.
+func parseTagValue(s *Schema, val string) (any, error) {
+	switch {
+	case slices.Contains(s.Type, TypeString):
+		return val, nil
+	case slices.Contains(s.Type, TypeInteger):
+		return strconv.ParseInt(val, 10, 64)
+	case slices.Contains(s.Type, TypeNumber):
+		return strconv.ParseFloat(val, 64)
+	case slices.Contains(s.Type, TypeBoolean):
+		return strconv.ParseBool(val)
+	}
+	var v any
+	if decodeJSON([]byte(val), &v) != nil {
+		return val, nil
+	}
+	return v, nil
+}
+
+// parseFlag parses the value of a boolean keyword. A keyword without a value is true.
+func parseFlag(val string) (bool, error) {
+	if val == "" {
+		return true, nil
+	}
+	return strconv.ParseBool(val)
+}
+
+// parseNumber checks that the value is a JSON number and keeps it as written.
+func parseNumber(val string) (json.Number, error) {
+	_, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return "", err
+	}
+	if !json.Valid([]byte(val)) {
+		return "", fmt.Errorf("%q is not a JSON number", val)
+	}
+	return json.Number(val), nil
+}
+
+func parseInt(val string) (*int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// splitEscaped splits the string by the separator, skipping the separators escaped with a backslash.
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == sep || s[i+1] == '\\'):
+			i++
+			part.WriteByte(s[i])
+		case s[i] == sep:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(s[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// Parameters generates parameters in the given location for the fields of the struct type.
+//
+// The name of a parameter is taken from the tag named after the location
+// (like `query:"page"` or `header:"X-Request-ID"`), falling back to the json tag.
+// Fields with the location tag are included even if they are skipped in JSON with `json:"-"`.
+// The schema of each parameter is generated the same way as for struct properties,
+// including the "openapi" and "validate" tags. Path parameters are always required.
+func (r *Reflector) Parameters(t reflect.Type, in Location) ([]RefOr[Parameter], error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %s", t)
+	}
+	var params []RefOr[Parameter]
+	for _, f := range structFields(t, string(in)) {
+		s, required, err := r.fieldSchema(f)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", f.goName, t, err)
+		}
+		param := Parameter{
+			Name:        f.name,
+			In:          in,
+			Description: s.Description,
+			Deprecated:  s.Deprecated,
+			Schema:      s,
+		}
+		if required || in == InPath {
+			required = true
+			param.Required = &required
+		}
+		params = append(params, Inline(param))
+	}
+	return params, nil
+}
+
+// Headers generates headers for the fields of the struct type.
+//
+// The name of a header is taken from the "header" tag, falling back to the json tag.
+func (r *Reflector) Headers(t reflect.Type) (map[string]RefOr[Header], error) {
+	params, err := r.Parameters(t, InHeader)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]RefOr[Header], len(params))
+	for _, param := range params {
+		p := param.Value
+		headers[p.Name] = Inline(Header{
+			Description: p.Description,
+			Required:    p.Required,
+			Deprecated:  p.Deprecated,
+			Schema:      p.Schema,
+		})
+	}
+	return headers, nil
+}
+
+// splitOneOf splits the values of the oneof rule by spaces.
+// Like in go-playground/validator, values with spaces are quoted with single quotes, like 'a b'.
+func splitOneOf(s string) []string {
+	var values []string
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return values
+		}
+		if s[0] == '\'' {
+			if value, rest, ok := strings.Cut(s[1:], "'"); ok {
+				values = append(values, value)
+				s = rest
+				continue
+			}
+		}
+		value, rest, _ := strings.Cut(s, " ")
+		values = append(values, value)
+		s = rest
+	}
+}
@@ -0,0 +1,84 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+type CreateUser struct {
+	ID     string   `json:"id" openapi:"description=The user ID\\, generated by the server,format=uuid,readOnly"`
+	Name   string   `json:"name,omitempty" openapi:"example=Aragorn,maxLength=64" validate:"required,min=1"`
+	Role   string   `json:"role" openapi:"enum=admin|user|guest,default=user"`
+	Age    int      `json:"age,omitempty" validate:"gte=18,lt=150"`
+	Emails []string `json:"emails" validate:"max=4,dive,email"`
+	Old    bool     `json:"old" openapi:"deprecated"`
+}
+
+func TestReflector_Annotations(t *testing.T) {
+	s, err := openapi.SchemaFor[CreateUser]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(s.Defs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"CreateUser": {
+		"type": "object",
+		"required": ["id", "name", "role", "emails", "old"],
+		"properties": {
+			"id": {"type": "string", "description": "The user ID, generated by the server", "format": "uuid", "readOnly": true},
+			"name": {"type": "string", "example": "Aragorn", "maxLength": 64, "minLength": 1},
+			"role": {"type": "string", "enum": ["admin", "user", "guest"], "default": "user"},
+			"age": {"type": "integer", "format": "int64", "minimum": 18, "exclusiveMaximum": 150},
+			"emails": {"type": "array", "items": {"type": "string"}, "maxItems": 4},
+			"old": {"type": "boolean", "deprecated": true}
+		}
+	}}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected schema: %s", got)
+	}
+}
+
+func TestReflector_Annotations_UnknownKeyword(t *testing.T) {
+	type Bad struct {
+		Name string `openapi:"maxLenght=4"`
+	}
+	_, err := openapi.SchemaFor[Bad]()
+	if err == nil {
+		t.Error("expected an error for an unknown keyword")
+	}
+}
+
+func TestReflector_Parameters(t *testing.T) {
+	type Query struct {
+		Page   int    `query:"page" json:"-" openapi:"minimum=1,description=Page number"`
+		Order  string `json:"order" validate:"required,oneof=asc desc"`
+		Filter string `query:"filter" validate:"oneof='first name' age"`
+		Secret string `json:"secret" query:"-"`
+		Body   string `json:"-"`
+	}
+	r := openapi.Reflector{}
+	params, err := r.Parameters(reflect.TypeFor[Query](), "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+		{"name": "page", "in": "query", "description": "Page number",
+		 "schema": {"type": "integer", "format": "int64", "minimum": 1, "description": "Page number"}},
+		{"name": "order", "in": "query", "required": true,
+		 "schema": {"type": "string", "enum": ["asc", "desc"]}},
+		{"name": "filter", "in": "query",
+		 "schema": {"type": "string", "enum": ["first name", "age"]}}
+	]`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected parameters: %s", got)
+	}
+}
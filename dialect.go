@@ -0,0 +1,75 @@
+package openapi
+
+import "strings"
+
+// URIs of the JSON Schema dialects of Schema Objects.
+const (
+	// Schema Objects of OpenAPI 3.0: an extended subset of JSON Schema draft 4
+	// with nullable and without $ref siblings. The specification defines no URI for it,
+	// so it's the ID of the OpenAPI 3.0 meta-schema.
+	DialectOAS30 = "https://spec.openapis.org/oas/3.0/schema/2021-09-28"
+	// The default dialect of OpenAPI 3.1: JSON Schema 2020-12 with the OpenAPI vocabulary.
+	DialectOAS31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+	// JSON Schema 2020-12 without the OpenAPI vocabulary.
+	DialectJSONSchema202012 = "https://json-schema.org/draft/2020-12/schema"
+	// JSON Schema draft 4.
+	DialectJSONSchemaDraft4 = "http://json-schema.org/draft-04/schema#"
+)
+
+// SchemaDialect returns the URI of the JSON Schema dialect of the Schema Objects in the document.
+//
+// It's [DialectOAS30] for OpenAPI 3.0 documents. For later versions,
+// it's JSONSchemaDialect if set and [DialectOAS31] otherwise.
+func (o *OpenAPI) SchemaDialect() string {
+	if strings.HasPrefix(o.Version, "3.0.") {
+		return DialectOAS30
+	}
+	if o.JSONSchemaDialect != "" {
+		return o.JSONSchemaDialect
+	}
+	return DialectOAS31
+}
+
+// A JSON Schema dialect, which defines the meaning of the keywords.
+type dialect int
+
+const (
+	// JSON Schema draft 4, used by the OAS 3.0 meta-schema.
+	dialectDraft4 dialect = iota
+	// Schema Objects of OpenAPI 3.0.
+	dialectOAS30
+	// JSON Schema 2020-12, used by OAS 3.1.
+	dialectDraft2020
+)
+
+// dialectOf returns the dialect for its URI.
+func dialectOf(uri string) (dialect, bool) {
+	switch strings.TrimSuffix(uri, "#") {
+	case DialectOAS30:
+		return dialectOAS30, true
+	case DialectOAS31, strings.TrimSuffix(DialectJSONSchema202012, "#"):
+		return dialectDraft2020, true
+	case strings.TrimSuffix(DialectJSONSchemaDraft4, "#"):
+		return dialectDraft4, true
+	}
+	return 0, false
+}
+
+// legacy reports if the dialect follows the rules of JSON Schema draft 4:
+// $ref overrides its siblings, exclusiveMaximum and exclusiveMinimum are booleans,
+// and keywords introduced later, like const and if, are ignored.
+func (d dialect) legacy() bool {
+	return d == dialectDraft4 || d == dialectOAS30
+}
+
+// idKeyword returns the keyword that changes the base URI of a schema, if there is one.
+func (d dialect) idKeyword() string {
+	switch d {
+	case dialectDraft4:
+		return "id"
+	case dialectOAS30:
+		// Schema Objects of OpenAPI 3.0 have no identifiers.
+		return ""
+	}
+	return "$id"
+}
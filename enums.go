@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Location is where a parameter or an API key is sent in a request.
+type Location string
+
+// Possible values of [Location].
+const (
+	InQuery  Location = "query"
+	InHeader Location = "header"
+	InPath   Location = "path"
+	InCookie Location = "cookie"
+)
+
+var locations = []Location{InQuery, InHeader, InPath, InCookie}
+
+// MarshalJSON encodes the location as a JSON string.
+// It fails for values other than the constants of [Location], except the empty one.
+func (l Location) MarshalJSON() ([]byte, error) {
+	return marshalEnum(l, locations, "location")
+}
+
+// UnmarshalJSON decodes the location from a JSON string.
+// It fails for values other than the constants of [Location], except the empty one.
+func (l *Location) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, l, locations, "location")
+}
+
+// Style describes how a parameter value is serialized depending on its type.
+type Style string
+
+// Possible values of [Style].
+const (
+	// Path-style parameters defined by RFC 6570, like ";color=blue".
+	StyleMatrix Style = "matrix"
+	// Label style parameters defined by RFC 6570, like ".blue".
+	StyleLabel Style = "label"
+	// Simple style parameters defined by RFC 6570, like "blue,black".
+	StyleSimple Style = "simple"
+	// Form style parameters defined by RFC 6570, like "color=blue".
+	StyleForm Style = "form"
+	// Space separated array values, like "color=blue%20black".
+	StyleSpaceDelimited Style = "spaceDelimited"
+	// Pipe separated array values, like "color=blue|black".
+	StylePipeDelimited Style = "pipeDelimited"
+	// Nested objects in the query, like "color[R]=100".
+	StyleDeepObject Style = "deepObject"
+)
+
+// Locations each style can be used in.
+var styleLocations = map[Style][]Location{
+	StyleMatrix:         {InPath},
+	StyleLabel:          {InPath},
+	StyleSimple:         {InPath, InHeader},
+	StyleForm:           {InQuery, InCookie},
+	StyleSpaceDelimited: {InQuery},
+	StylePipeDelimited:  {InQuery},
+	StyleDeepObject:     {InQuery},
+}
+
+var styles = []Style{
+	StyleMatrix, StyleLabel, StyleSimple, StyleForm,
+	StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject,
+}
+
+// AllowedIn reports if the style can be used for parameters in the location,
+// like [StyleDeepObject] only in the query.
+func (s Style) AllowedIn(in Location) bool {
+	return slices.Contains(styleLocations[s], in)
+}
+
+// MarshalJSON encodes the style as a JSON string.
+// It fails for values other than the constants of [Style], except the empty one.
+func (s Style) MarshalJSON() ([]byte, error) {
+	return marshalEnum(s, styles, "style")
+}
+
+// UnmarshalJSON decodes the style from a JSON string.
+// It fails for values other than the constants of [Style], except the empty one.
+func (s *Style) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, styles, "style")
+}
+
+// SecurityType is the type of a [SecurityScheme].
+type SecurityType string
+
+// Possible values of [SecurityType].
+const (
+	SecurityTypeAPIKey        SecurityType = "apiKey"
+	SecurityTypeHTTP          SecurityType = "http"
+	SecurityTypeMutualTLS     SecurityType = "mutualTLS"
+	SecurityTypeOAuth2        SecurityType = "oauth2"
+	SecurityTypeOpenIDConnect SecurityType = "openIdConnect"
+)
+
+var securityTypes = []SecurityType{
+	SecurityTypeAPIKey, SecurityTypeHTTP, SecurityTypeMutualTLS,
+	SecurityTypeOAuth2, SecurityTypeOpenIDConnect,
+}
+
+// MarshalJSON encodes the security scheme type as a JSON string.
+// It fails for values other than the constants of [SecurityType], except the empty one.
+func (t SecurityType) MarshalJSON() ([]byte, error) {
+	return marshalEnum(t, securityTypes, "security scheme type")
+}
+
+// UnmarshalJSON decodes the security scheme type from a JSON string.
+// It fails for values other than the constants of [SecurityType], except the empty one.
+func (t *SecurityType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t, securityTypes, "security scheme type")
+}
+
+// marshalEnum encodes the value as a JSON string if it's one of the valid values.
+// The empty value means the field is not set and is allowed.
+func marshalEnum[T ~string](v T, valid []T, what string) ([]byte, error) {
+	if v != "" && !slices.Contains(valid, v) {
+		return nil, fmt.Errorf("invalid %s %q", what, string(v))
+	}
+	return json.Marshal(string(v))
+}
+
+// unmarshalEnum decodes the JSON string into the value if it's one of the valid values.
+func unmarshalEnum[T ~string](data []byte, v *T, valid []T, what string) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("%s must be a string", what)
+	}
+	if s != "" && !slices.Contains(valid, T(s)) {
+		return fmt.Errorf("invalid %s %q", what, s)
+	}
+	*v = T(s)
+	return nil
+}
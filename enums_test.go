@@ -0,0 +1,76 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestEnums_JSON(t *testing.T) {
+	p := openapi.Parameter{Name: "filter", In: openapi.InQuery, Style: openapi.StyleDeepObject}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"filter","in":"query","style":"deepObject"}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+	var got openapi.Parameter
+	err = json.Unmarshal(data, &got)
+	if err != nil || got.In != openapi.InQuery || got.Style != openapi.StyleDeepObject {
+		t.Errorf("unexpected round trip: %+v, %v", got, err)
+	}
+
+	invalid := []any{
+		openapi.Parameter{Name: "q", In: "querry"},
+		openapi.Parameter{Name: "q", In: openapi.InQuery, Style: "deep"},
+		openapi.Header{Style: "Simple"},
+		openapi.SecurityScheme{Type: "Bearer"},
+		openapi.SecurityScheme{Type: openapi.SecurityTypeAPIKey, In: "body"},
+	}
+	for _, v := range invalid {
+		_, err := json.Marshal(v)
+		if err == nil {
+			t.Errorf("expected an error for %+v", v)
+		}
+	}
+
+	for _, data := range []string{
+		`{"name":"q","in":"querry"}`,
+		`{"name":"q","in":"query","style":"deep"}`,
+		`{"name":"q","in":1}`,
+	} {
+		var p openapi.Parameter
+		if json.Unmarshal([]byte(data), &p) == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+	var s openapi.SecurityScheme
+	if json.Unmarshal([]byte(`{"type":"Bearer"}`), &s) == nil {
+		t.Error("expected an error for an invalid security scheme type")
+	}
+}
+
+func TestStyle_AllowedIn(t *testing.T) {
+	tests := []struct {
+		style openapi.Style
+		in    openapi.Location
+		want  bool
+	}{
+		{openapi.StyleDeepObject, openapi.InQuery, true},
+		{openapi.StyleDeepObject, openapi.InHeader, false},
+		{openapi.StyleMatrix, openapi.InPath, true},
+		{openapi.StyleMatrix, openapi.InQuery, false},
+		{openapi.StyleSimple, openapi.InHeader, true},
+		{openapi.StyleForm, openapi.InCookie, true},
+		{openapi.StyleForm, openapi.InPath, false},
+		{"unknown", openapi.InQuery, false},
+	}
+	for _, tt := range tests {
+		if got := tt.style.AllowedIn(tt.in); got != tt.want {
+			t.Errorf("%s in %s: got %v, want %v", tt.style, tt.in, got, tt.want)
+		}
+	}
+}
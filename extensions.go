@@ -0,0 +1,360 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// checkExtensions returns an error if any of the extension keys doesn't begin with "x-".
+func checkExtensions(ext map[string]any) error {
+	for key := range ext {
+		if !strings.HasPrefix(key, "x-") {
+			return fmt.Errorf("extension %q must begin with \"x-\"", key)
+		}
+	}
+	return nil
+}
+
+// marshalExtensions encodes the object with extensions inlined into it.
+func marshalExtensions(v any, ext map[string]any) ([]byte, error) {
+	err := checkExtensions(ext)
+	if err != nil {
+		return nil, err
+	}
+	return marshalInline(v, ext)
+}
+
+// unmarshalExtensions decodes the object and collects the extensions from it.
+// Unknown fields that aren't extensions are ignored.
+func unmarshalExtensions(data []byte, v any, ext *map[string]any) error {
+	unknown, err := unmarshalInline(data, v)
+	if err != nil {
+		return err
+	}
+	*ext = nil
+	for key, raw := range unknown {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		var val any
+		err = decodeJSON(raw, &val)
+		if err != nil {
+			return err
+		}
+		if *ext == nil {
+			*ext = make(map[string]any)
+		}
+		(*ext)[key] = val
+	}
+	return nil
+}
+
+// marshalPathItems encodes a JSON object with path items as values and extensions inlined into it.
+func marshalPathItems(items map[string]PathItem, ext map[string]any) ([]byte, error) {
+	for key := range items {
+		if strings.HasPrefix(key, "x-") {
+			return nil, fmt.Errorf("the key %q of a path item is reserved for extensions", key)
+		}
+	}
+	if items == nil {
+		items = map[string]PathItem{}
+	}
+	return marshalExtensions(items, ext)
+}
+
+// unmarshalPathItems decodes a JSON object with path items as values and extensions.
+func unmarshalPathItems(data []byte) (map[string]PathItem, map[string]any, error) {
+	var raw map[string]json.RawMessage
+	err := decodeJSON(data, &raw)
+	if err != nil || raw == nil {
+		return nil, nil, err
+	}
+	items := make(map[string]PathItem, len(raw))
+	var ext map[string]any
+	for key, val := range raw {
+		if strings.HasPrefix(key, "x-") {
+			var v any
+			err = decodeJSON(val, &v)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", key, err)
+			}
+			if ext == nil {
+				ext = make(map[string]any)
+			}
+			ext[key] = v
+			continue
+		}
+		var item PathItem
+		err = decodeJSON(val, &item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", key, err)
+		}
+		items[key] = item
+	}
+	return items, ext, nil
+}
+
+func (o OpenAPI) MarshalJSON() ([]byte, error) {
+	type raw OpenAPI
+	return marshalExtensions(raw(o), o.Extensions)
+}
+
+func (o *OpenAPI) UnmarshalJSON(data []byte) error {
+	type raw OpenAPI
+	return unmarshalExtensions(data, (*raw)(o), &o.Extensions)
+}
+
+func (i Info) MarshalJSON() ([]byte, error) {
+	type raw Info
+	return marshalExtensions(raw(i), i.Extensions)
+}
+
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type raw Info
+	return unmarshalExtensions(data, (*raw)(i), &i.Extensions)
+}
+
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type raw Contact
+	return marshalExtensions(raw(c), c.Extensions)
+}
+
+func (c *Contact) UnmarshalJSON(data []byte) error {
+	type raw Contact
+	return unmarshalExtensions(data, (*raw)(c), &c.Extensions)
+}
+
+func (l License) MarshalJSON() ([]byte, error) {
+	type raw License
+	return marshalExtensions(raw(l), l.Extensions)
+}
+
+func (l *License) UnmarshalJSON(data []byte) error {
+	type raw License
+	return unmarshalExtensions(data, (*raw)(l), &l.Extensions)
+}
+
+func (s Server) MarshalJSON() ([]byte, error) {
+	type raw Server
+	return marshalExtensions(raw(s), s.Extensions)
+}
+
+func (s *Server) UnmarshalJSON(data []byte) error {
+	type raw Server
+	return unmarshalExtensions(data, (*raw)(s), &s.Extensions)
+}
+
+func (s ServerVariable) MarshalJSON() ([]byte, error) {
+	type raw ServerVariable
+	return marshalExtensions(raw(s), s.Extensions)
+}
+
+func (s *ServerVariable) UnmarshalJSON(data []byte) error {
+	type raw ServerVariable
+	return unmarshalExtensions(data, (*raw)(s), &s.Extensions)
+}
+
+func (c Components) MarshalJSON() ([]byte, error) {
+	type raw Components
+	return marshalExtensions(raw(c), c.Extensions)
+}
+
+func (c *Components) UnmarshalJSON(data []byte) error {
+	type raw Components
+	return unmarshalExtensions(data, (*raw)(c), &c.Extensions)
+}
+
+func (p Paths) MarshalJSON() ([]byte, error) {
+	return marshalPathItems(p.Items, p.Extensions)
+}
+
+func (p *Paths) UnmarshalJSON(data []byte) error {
+	var err error
+	p.Items, p.Extensions, err = unmarshalPathItems(data)
+	return err
+}
+
+func (c Callback) MarshalJSON() ([]byte, error) {
+	return marshalPathItems(c.Items, c.Extensions)
+}
+
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var err error
+	c.Items, c.Extensions, err = unmarshalPathItems(data)
+	return err
+}
+
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type raw PathItem
+	return marshalExtensions(raw(p), p.Extensions)
+}
+
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	type raw PathItem
+	return unmarshalExtensions(data, (*raw)(p), &p.Extensions)
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type raw Operation
+	return marshalExtensions(raw(o), o.Extensions)
+}
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type raw Operation
+	return unmarshalExtensions(data, (*raw)(o), &o.Extensions)
+}
+
+func (e ExternalDoc) MarshalJSON() ([]byte, error) {
+	type raw ExternalDoc
+	return marshalExtensions(raw(e), e.Extensions)
+}
+
+func (e *ExternalDoc) UnmarshalJSON(data []byte) error {
+	type raw ExternalDoc
+	return unmarshalExtensions(data, (*raw)(e), &e.Extensions)
+}
+
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type raw Parameter
+	return marshalExtensions(raw(p), p.Extensions)
+}
+
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type raw Parameter
+	return unmarshalExtensions(data, (*raw)(p), &p.Extensions)
+}
+
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	type raw RequestBody
+	return marshalExtensions(raw(r), r.Extensions)
+}
+
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	type raw RequestBody
+	return unmarshalExtensions(data, (*raw)(r), &r.Extensions)
+}
+
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	type raw MediaType
+	return marshalExtensions(raw(m), m.Extensions)
+}
+
+func (m *MediaType) UnmarshalJSON(data []byte) error {
+	type raw MediaType
+	return unmarshalExtensions(data, (*raw)(m), &m.Extensions)
+}
+
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	type raw Encoding
+	return marshalExtensions(raw(e), e.Extensions)
+}
+
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	type raw Encoding
+	return unmarshalExtensions(data, (*raw)(e), &e.Extensions)
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	type raw Response
+	return marshalExtensions(raw(r), r.Extensions)
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type raw Response
+	return unmarshalExtensions(data, (*raw)(r), &r.Extensions)
+}
+
+func (e Example) MarshalJSON() ([]byte, error) {
+	type raw Example
+	return marshalExtensions(raw(e), e.Extensions)
+}
+
+func (e *Example) UnmarshalJSON(data []byte) error {
+	type raw Example
+	return unmarshalExtensions(data, (*raw)(e), &e.Extensions)
+}
+
+func (l Link) MarshalJSON() ([]byte, error) {
+	type raw Link
+	return marshalExtensions(raw(l), l.Extensions)
+}
+
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type raw Link
+	return unmarshalExtensions(data, (*raw)(l), &l.Extensions)
+}
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	type raw Header
+	return marshalExtensions(raw(h), h.Extensions)
+}
+
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type raw Header
+	return unmarshalExtensions(data, (*raw)(h), &h.Extensions)
+}
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type raw Tag
+	return marshalExtensions(raw(t), t.Extensions)
+}
+
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type raw Tag
+	return unmarshalExtensions(data, (*raw)(t), &t.Extensions)
+}
+
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type raw SecurityScheme
+	return marshalExtensions(raw(s.relevant()), s.Extensions)
+}
+
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	type raw SecurityScheme
+	return unmarshalExtensions(data, (*raw)(s), &s.Extensions)
+}
+
+func (f OAuthFlows) MarshalJSON() ([]byte, error) {
+	type raw OAuthFlows
+	f.Implicit.TokenURL = ""
+	f.Password.AuthorizationURL = ""
+	f.ClientCredentials.AuthorizationURL = ""
+	return marshalExtensions(raw(f), f.Extensions)
+}
+
+func (f *OAuthFlows) UnmarshalJSON(data []byte) error {
+	type raw OAuthFlows
+	return unmarshalExtensions(data, (*raw)(f), &f.Extensions)
+}
+
+func (f OAuthFlow) MarshalJSON() ([]byte, error) {
+	type raw OAuthFlow
+	f.Scopes = nonNilScopes(f.Scopes)
+	return marshalExtensions(raw(f), f.Extensions)
+}
+
+func (f *OAuthFlow) UnmarshalJSON(data []byte) error {
+	type raw OAuthFlow
+	return unmarshalExtensions(data, (*raw)(f), &f.Extensions)
+}
+
+func (d Discriminator) MarshalJSON() ([]byte, error) {
+	type raw Discriminator
+	return marshalExtensions(raw(d), d.Extensions)
+}
+
+func (d *Discriminator) UnmarshalJSON(data []byte) error {
+	type raw Discriminator
+	return unmarshalExtensions(data, (*raw)(d), &d.Extensions)
+}
+
+func (x XML) MarshalJSON() ([]byte, error) {
+	type raw XML
+	return marshalExtensions(raw(x), x.Extensions)
+}
+
+func (x *XML) UnmarshalJSON(data []byte) error {
+	type raw XML
+	return unmarshalExtensions(data, (*raw)(x), &x.Extensions)
+}
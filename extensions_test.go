@@ -0,0 +1,80 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestExtensions(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info: openapi.Info{
+			Title:      "Pets",
+			Version:    "1.0",
+			Extensions: map[string]any{"x-logo": map[string]string{"url": "logo.png"}},
+		},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets": openapi.PathItem{
+				Get: openapi.Operation{
+					Extensions: map[string]any{
+						"x-internal":  true,
+						"x-rateLimit": 100,
+					},
+				},
+			},
+		}},
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Pet": {Extensions: map[string]any{"x-go-type": "Pet"}},
+			},
+		},
+		Extensions: map[string]any{
+			"x-tagGroups": []any{map[string]any{"name": "Pets", "tags": []string{"pets"}}},
+		},
+	}
+	got, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+		"openapi": "3.1.0",
+		"info": {"title": "Pets", "version": "1.0", "x-logo": {"url": "logo.png"}},
+		"paths": {"/pets": {"get": {"x-internal": true, "x-rateLimit": 100}}},
+		"components": {"schemas": {"Pet": {"x-go-type": "Pet"}}},
+		"x-tagGroups": [{"name": "Pets", "tags": ["pets"]}]
+	}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected document: %s", got)
+	}
+
+	var parsed openapi.OpenAPI
+	err = json.Unmarshal(got, &parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := parsed.Paths.Items["/pets"].Get
+	if op.Extensions["x-internal"] != true || op.Extensions["x-rateLimit"] != json.Number("100") {
+		t.Errorf("unexpected operation extensions: %v", op.Extensions)
+	}
+	if parsed.Components.Schemas["Pet"].Extensions["x-go-type"] != "Pet" {
+		t.Errorf("unexpected schema: %+v", parsed.Components.Schemas["Pet"])
+	}
+	if len(parsed.Extensions) != 1 {
+		t.Errorf("unexpected document extensions: %v", parsed.Extensions)
+	}
+}
+
+func TestExtensions_InvalidKey(t *testing.T) {
+	values := []any{
+		openapi.Tag{Name: "pets", Extensions: map[string]any{"internal": true}},
+		openapi.Schema{Extensions: map[string]any{"go-type": "Pet"}},
+	}
+	for _, v := range values {
+		_, err := json.Marshal(v)
+		if err == nil {
+			t.Errorf("expected an error for %+v", v)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FormatChecker checks that the value matches a format.
+//
+// The value is a generic JSON value: nil, bool, string, [json.Number], float64, []any, or map[string]any.
+// The checker must return nil for the types the format doesn't apply to,
+// so that a string format accepts numbers and a numeric format accepts strings.
+type FormatChecker func(value any) error
+
+// Formats is a registry of format checkers by the format name.
+type Formats map[string]FormatChecker
+
+// DefaultFormats returns a new registry with checkers for the formats
+// defined by JSON Schema and the OpenAPI Format Registry:
+// date-time, date, time, duration, email, idn-email, hostname, ipv4, ipv6,
+// uri, uri-reference, uuid, regex, json-pointer, int32, int64, float, double, and byte.
+//
+// The registry can be extended with custom formats.
+func DefaultFormats() Formats {
+	return Formats{
+		"date-time":     stringFormat(checkDateTime),
+		"date":          stringFormat(checkDate),
+		"time":          stringFormat(checkTime),
+		"duration":      stringFormat(checkDuration),
+		"email":         stringFormat(checkEmail),
+		"idn-email":     stringFormat(checkIDNEmail),
+		"hostname":      stringFormat(checkHostname),
+		"ipv4":          stringFormat(checkIPv4),
+		"ipv6":          stringFormat(checkIPv6),
+		"uri":           stringFormat(checkURI),
+		"uri-reference": stringFormat(checkURIReference),
+		"uuid":          stringFormat(checkUUID),
+		"regex":         stringFormat(checkRegex),
+		"json-pointer":  stringFormat(checkJSONPointer),
+		"int32":         intFormat(math.MinInt32, math.MaxInt32),
+		"int64":         intFormat(math.MinInt64, math.MaxInt64),
+		"float":         floatFormat(math.MaxFloat32),
+		"double":        floatFormat(math.MaxFloat64),
+		"byte":          stringFormat(checkByte),
+	}
+}
+
+// stringFormat wraps the check for a string format into a [FormatChecker] that ignores other types.
+func stringFormat(check func(string) error) FormatChecker {
+	return func(value any) error {
+		s, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		return check(s)
+	}
+}
+
+// RFC 3339 allows lowercase "t" and "z", which the time package doesn't accept.
+func checkDateTime(s string) error {
+	_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(s))
+	if err != nil {
+		return errors.New("expected an RFC 3339 date-time, like 2006-01-02T15:04:05Z")
+	}
+	return nil
+}
+
+func checkDate(s string) error {
+	_, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return errors.New("expected an RFC 3339 full-date, like 2006-01-02")
+	}
+	return nil
+}
+
+func checkTime(s string) error {
+	_, err := time.Parse("15:04:05.999999999Z07:00", strings.ToUpper(s))
+	if err != nil {
+		return errors.New("expected an RFC 3339 full-time, like 15:04:05Z")
+	}
+	return nil
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:\d+W|(?:\d+Y)?(?:\d+M)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+S)?)?)$`)
+
+// The duration has at least one component and at least one time component after "T".
+func checkDuration(s string) error {
+	if !durationPattern.MatchString(s) || s == "P" || strings.HasSuffix(s, "T") {
+		return errors.New("expected an ISO 8601 duration, like P1DT12H")
+	}
+	return nil
+}
+
+func checkEmail(s string) error {
+	for i := range len(s) {
+		if s[i] >= utf8.RuneSelf {
+			return errors.New("expected an email address with only ASCII characters")
+		}
+	}
+	return checkIDNEmail(s)
+}
+
+func checkIDNEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return errors.New("expected an email address, like user@example.com")
+	}
+	return nil
+}
+
+// checkHostname checks the host name by RFC 1123.
+func checkHostname(s string) error {
+	if s == "" || len(strings.TrimSuffix(s, ".")) > 253 {
+		return errors.New("expected a host name of 1 to 253 characters")
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("expected host name labels of 1 to 63 characters, got %q", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("the host name label %q starts or ends with a hyphen", label)
+		}
+		for _, c := range label {
+			if c != '-' && !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+				return fmt.Errorf("the host name label %q has an invalid character %q", label, c)
+			}
+		}
+	}
+	return nil
+}
+
+func checkIPv4(s string) error {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is4() {
+		return errors.New("expected an IPv4 address, like 192.0.2.1")
+	}
+	return nil
+}
+
+func checkIPv6(s string) error {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return errors.New("expected an IPv6 address, like 2001:db8::1")
+	}
+	return nil
+}
+
+func checkURI(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return errors.New("expected an absolute URI with a scheme")
+	}
+	return nil
+}
+
+func checkURIReference(s string) error {
+	_, err := url.Parse(s)
+	return err
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func checkUUID(s string) error {
+	if !uuidPattern.MatchString(s) {
+		return errors.New("expected a UUID, like 123e4567-e89b-12d3-a456-426614174000")
+	}
+	return nil
+}
+
+func checkRegex(s string) error {
+	// Unlike the patterns of the schemas, the values aren't cached
+	// since they come from the clients.
+	_, err := regexp.Compile(s)
+	return err
+}
+
+func checkJSONPointer(s string) error {
+	if s != "" && s[0] != '/' {
+		return errors.New(`expected a JSON Pointer starting with "/"`)
+	}
+	for i := range len(s) {
+		if s[i] == '~' && (i+1 == len(s) || s[i+1] != '0' && s[i+1] != '1') {
+			return errors.New(`"~" must be followed by "0" or "1"`)
+		}
+	}
+	return nil
+}
+
+func checkByte(s string) error {
+	_, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return errors.New("expected a base64-encoded string")
+	}
+	return nil
+}
+
+// intFormat checks that a number is an integer in the range.
+func intFormat(min, max int64) FormatChecker {
+	return func(value any) error {
+		if n, ok := value.(json.Number); ok {
+			i, err := strconv.ParseInt(string(n), 10, 64)
+			if err == nil {
+				if i < min || i > max {
+					return fmt.Errorf("the integer is out of range [%d, %d]", min, max)
+				}
+				return nil
+			}
+		}
+		f, ok := toFloat(value)
+		if !ok {
+			return nil
+		}
+		if f != math.Trunc(f) {
+			return errors.New("expected an integer")
+		}
+		// float64(math.MaxInt64) rounds up to 2^63, so the upper bound is checked against -min instead.
+		if f < float64(min) || f >= -float64(min) {
+			return fmt.Errorf("the integer is out of range [%d, %d]", min, max)
+		}
+		return nil
+	}
+}
+
+// floatFormat checks that a number is representable as a float with the maximum magnitude.
+func floatFormat(max float64) FormatChecker {
+	return func(value any) error {
+		f, ok := toFloat(value)
+		if !ok {
+			return nil
+		}
+		if math.IsInf(f, 0) || math.Abs(f) > max {
+			return fmt.Errorf("the number is out of range [%g, %g]", -max, max)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestDefaultFormats(t *testing.T) {
+	tests := []struct {
+		format  string
+		valid   []any
+		invalid []any
+	}{
+		{"date-time", []any{"2024-02-29T12:30:00Z", "2024-02-29t12:30:00.123+02:00"}, []any{"2024-02-30T12:30:00Z", "2024-02-29 12:30:00Z", "2024-02-29T12:30:00"}},
+		{"date", []any{"2024-02-29"}, []any{"2023-02-29", "2024-2-1"}},
+		{"time", []any{"12:30:00Z", "23:59:59.5-07:00"}, []any{"12:30:00", "25:00:00Z"}},
+		{"duration", []any{"P1D", "PT1H30M", "P2W", "P1Y2M3DT4H5M6S"}, []any{"P", "PT", "P1DT", "1D", "P1W2D"}},
+		{"email", []any{"user@example.com", "first.last+tag@example.co.uk"}, []any{"user", "User <user@example.com>", "юзер@example.com"}},
+		{"idn-email", []any{"юзер@пример.рф"}, []any{"@example.com"}},
+		{"hostname", []any{"example.com", "a-b.example.com.", "localhost"}, []any{"-a.example.com", "a..b", "under_score.com"}},
+		{"ipv4", []any{"192.0.2.1"}, []any{"192.0.2.256", "01.2.3.4", "::1"}},
+		{"ipv6", []any{"2001:db8::1", "::1"}, []any{"192.0.2.1", "fe80::1%eth0", "2001:db8:::1"}},
+		{"uri", []any{"https://example.com/a?b#c", "urn:isbn:0451450523"}, []any{"/relative", "http://[::1"}},
+		{"uri-reference", []any{"/relative", "#frag"}, []any{"http://[::1"}},
+		{"uuid", []any{"123e4567-e89b-12d3-a456-426614174000"}, []any{"123e4567e89b12d3a456426614174000"}},
+		{"regex", []any{"^[a-z]+$"}, []any{"(unclosed"}},
+		{"json-pointer", []any{"", "/a~1b/0", "/~0"}, []any{"a", "/~2", "/~"}},
+		{"int32", []any{json.Number("2147483647"), json.Number("-2147483648"), json.Number("1.0"), 5.0}, []any{json.Number("2147483648"), json.Number("1.5")}},
+		{"int64", []any{json.Number("9223372036854775807")}, []any{json.Number("9223372036854775808"), json.Number("1e19")}},
+		{"float", []any{json.Number("3.4e38"), 1.5}, []any{json.Number("3.5e38")}},
+		{"double", []any{json.Number("1.7e308")}, []any{json.Number("1e309")}},
+		{"byte", []any{"aGVsbG8=", ""}, []any{"aGVsbG8", "not base64!"}},
+	}
+	formats := openapi.DefaultFormats()
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			check := formats[tt.format]
+			if check == nil {
+				t.Fatal("no checker")
+			}
+			for _, v := range tt.valid {
+				if err := check(v); err != nil {
+					t.Errorf("unexpected error for %v: %v", v, err)
+				}
+			}
+			for _, v := range tt.invalid {
+				if check(v) == nil {
+					t.Errorf("expected an error for %v", v)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_Format(t *testing.T) {
+	v, err := openapi.NewSchemaValidator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &openapi.Schema{
+		Properties: map[string]*openapi.Schema{
+			"email":    {Type: openapi.Types{openapi.TypeString}, Format: "email"},
+			"password": {Type: openapi.Types{openapi.TypeString}, Format: "password"},
+			"country":  {Type: openapi.Types{openapi.TypeString}, Format: "iso3166"},
+			"count":    {Format: "int32"},
+		},
+	}
+	value := map[string]any{"email": "nope", "password": "secret", "country": "Narnia", "count": "many"}
+
+	// Annotation mode by default.
+	err = v.Validate(schema, value)
+	if err != nil {
+		t.Fatalf("unexpected error in annotation mode: %v", err)
+	}
+
+	v, err = openapi.NewSchemaValidator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.AssertFormat = true
+	v.Formats["iso3166"] = func(value any) error {
+		if s, ok := value.(string); ok && len(s) != 2 {
+			return errors.New("expected a two-letter country code")
+		}
+		return nil
+	}
+	err = v.Validate(schema, value)
+	var errs openapi.SchemaErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected two errors, got %v", err)
+	}
+	got := map[string]bool{}
+	for _, e := range errs {
+		got[e.KeywordLocation] = true
+	}
+	if !got["/properties/email/format"] || !got["/properties/country/format"] {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
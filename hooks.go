@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"time"
+)
+
+// JSONSchemaer is implemented by types that describe their own schema.
+//
+// [Reflector] uses the returned schema as is instead of generating it from the type structure.
+// The method is called on the zero value of the type.
+type JSONSchemaer interface {
+	JSONSchema() Schema
+}
+
+// Enumer is implemented by types that have a limited set of possible values.
+//
+// [Reflector] uses the returned values as the enum of the type schema.
+// The method is called on the zero value of the type.
+type Enumer interface {
+	Enum() []any
+}
+
+// Exampler is implemented by types that can provide an example value.
+//
+// [Reflector] uses the returned value as the example of the type schema.
+// The method is called on the zero value of the type.
+type Exampler interface {
+	Example() any
+}
+
+// Schemas for well-known types from the standard library
+// that encoding/json encodes differently from what their structure suggests.
+var builtinSchemas = map[reflect.Type]func() *Schema{
+	reflect.TypeFor[time.Time](): func() *Schema {
+		return &Schema{Type: Types{TypeString}, Format: "date-time"}
+	},
+	reflect.TypeFor[time.Duration](): func() *Schema {
+		// Duration is encoded as an integer number of nanoseconds.
+		return &Schema{Type: Types{TypeInteger}, Format: "int64"}
+	},
+	reflect.TypeFor[netip.Addr](): func() *Schema {
+		return &Schema{
+			Type:  Types{TypeString},
+			AnyOf: []*Schema{{Format: "ipv4"}, {Format: "ipv6"}},
+		}
+	},
+	reflect.TypeFor[netip.Prefix](): func() *Schema {
+		return &Schema{Type: Types{TypeString}}
+	},
+	reflect.TypeFor[netip.AddrPort](): func() *Schema {
+		return &Schema{Type: Types{TypeString}}
+	},
+	reflect.TypeFor[json.RawMessage](): func() *Schema {
+		return &Schema{}
+	},
+	reflect.TypeFor[big.Int](): func() *Schema {
+		// big.Int is encoded as a JSON number of arbitrary size.
+		return &Schema{Type: Types{TypeInteger}}
+	},
+}
+
+var (
+	jsonSchemaerType  = reflect.TypeFor[JSONSchemaer]()
+	enumerType        = reflect.TypeFor[Enumer]()
+	examplerType      = reflect.TypeFor[Exampler]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// customSchema returns the schema for the type if it isn't defined by the type structure.
+func customSchema(t reflect.Type) (*Schema, bool) {
+	if t.Kind() == reflect.Interface {
+		return nil, false
+	}
+	if implements(t, jsonSchemaerType) {
+		s := zero(t).(JSONSchemaer).JSONSchema()
+		return &s, true
+	}
+	if builtin, ok := builtinSchemas[t]; ok {
+		return builtin(), true
+	}
+	if implements(t, jsonMarshalerType) {
+		// There is no way to know what a custom marshaler produces.
+		return &Schema{}, true
+	}
+	if implements(t, textMarshalerType) {
+		return &Schema{Type: Types{TypeString}}, true
+	}
+	return nil, false
+}
+
+// applyHooks sets the enum and the example of the schema if the type provides them.
+func applyHooks(s *Schema, t reflect.Type) {
+	if t.Kind() == reflect.Interface {
+		return
+	}
+	if implements(t, enumerType) {
+		s.Enum = zero(t).(Enumer).Enum()
+	}
+	if implements(t, examplerType) {
+		s.Example = zero(t).(Exampler).Example()
+	}
+}
+
+// isMarshaler reports if encoding/json uses a custom marshaler for the type.
+func isMarshaler(t reflect.Type) bool {
+	return implements(t, jsonMarshalerType) || implements(t, textMarshalerType)
+}
+
+// implements reports if the type or a pointer to it implements the interface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// zero returns a pointer to the zero value of the type.
+//
+// The pointer has the methods of both the type and the pointer to it.
+func zero(t reflect.Type) any {
+	return reflect.New(t).Interface()
+}
+
+// bytesSchema is the schema for a byte slice, which encoding/json encodes as a base64 string.
+func bytesSchema() *Schema {
+	return &Schema{Type: Types{TypeString}, Format: "byte", ContentEncoding: "base64"}
+}
@@ -0,0 +1,89 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+type Color string
+
+func (Color) Enum() []any {
+	return []any{"red", "green", "blue"}
+}
+
+func (Color) Example() any {
+	return "red"
+}
+
+type Money struct {
+	Cents int64
+}
+
+func (Money) JSONSchema() openapi.Schema {
+	return openapi.Schema{Type: openapi.Types{openapi.TypeString}, Pattern: `^\d+\.\d{2}$`}
+}
+
+type Level int
+
+func (l *Level) MarshalText() ([]byte, error) {
+	return []byte("high"), nil
+}
+
+type Builtins struct {
+	Time     time.Time       `json:"time"`
+	Duration time.Duration   `json:"duration"`
+	Addr     netip.Addr      `json:"addr"`
+	Raw      json.RawMessage `json:"raw"`
+	Bytes    []byte          `json:"bytes"`
+	Big      *big.Int        `json:"big"`
+	Color    Color           `json:"color"`
+	Money    Money           `json:"money"`
+	Level    Level           `json:"level"`
+}
+
+func TestReflector_Hooks(t *testing.T) {
+	s, err := openapi.SchemaFor[Builtins]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(s.Defs["Builtins"].Properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+		"time": {"type": "string", "format": "date-time"},
+		"duration": {"type": "integer", "format": "int64"},
+		"addr": {"type": "string", "anyOf": [{"format": "ipv4"}, {"format": "ipv6"}]},
+		"raw": {},
+		"bytes": {"type": "string", "format": "byte", "contentEncoding": "base64"},
+		"big": {"type": ["integer", "null"]},
+		"color": {"type": "string", "enum": ["red", "green", "blue"], "example": "red"},
+		"money": {"type": "string", "pattern": "^\\d+\\.\\d{2}$"},
+		"level": {"type": "string"}
+	}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected schema: %s", got)
+	}
+	if len(s.Defs) != 1 {
+		t.Errorf("types with custom schemas must not be registered: %v", s.Defs)
+	}
+}
+
+func TestReflector_URL(t *testing.T) {
+	// encoding/json encodes url.URL as an object, not as a string.
+	s, err := openapi.SchemaFor[url.URL]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := s.Defs["URL"]
+	if schema == nil || !slices.Equal(schema.Type, openapi.Types{openapi.TypeObject}) || schema.Properties["Host"] == nil {
+		t.Errorf("unexpected schema: %+v", s)
+	}
+}
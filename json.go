@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Cache of JSON field names known for a struct type.
+var knownFields sync.Map
+
+// fieldNames returns the set of JSON object keys that encoding/json maps onto fields of the given struct type.
+func fieldNames(t reflect.Type) map[string]struct{} {
+	if cached, ok := knownFields.Load(t); ok {
+		return cached.(map[string]struct{})
+	}
+	names := make(map[string]struct{})
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = struct{}{}
+	}
+	knownFields.Store(t, names)
+	return names
+}
+
+// marshalInline encodes v as a JSON object and appends to it the given fields, sorted by key.
+func marshalInline(v any, extra map[string]any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, key := range keys {
+		rawKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		rawVal, err := json.Marshal(extra[key])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(rawKey)
+		buf.WriteByte(':')
+		buf.Write(rawVal)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalInline decodes the JSON object into v, which must be a pointer to a struct,
+// and returns the object fields that don't correspond to any field of v.
+func unmarshalInline(data []byte, v any) (map[string]json.RawMessage, error) {
+	err := decodeJSON(data, v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	err = decodeJSON(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+	known := fieldNames(reflect.TypeOf(v).Elem())
+	var unknown map[string]json.RawMessage
+	for key, val := range raw {
+		if _, ok := known[key]; ok {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+		unknown[key] = val
+	}
+	return unknown, nil
+}
+
+// decodeJSON is the same as [json.Unmarshal] but keeps numbers in untyped values as [json.Number]
+// so that they are encoded back exactly as they were.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
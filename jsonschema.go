@@ -0,0 +1,811 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A violation of a JSON Schema found while validating a value.
+type SchemaError struct {
+	// JSON Pointer to the invalid value, like "/paths/~1pets/get".
+	InstanceLocation string
+	// JSON Pointer to the failed keyword in the schema, through all followed references,
+	// like "/properties/paths/$ref/required".
+	KeywordLocation string
+	// The description of the violated constraint.
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("#%s: %s (keyword #%s)", e.InstanceLocation, e.Message, e.KeywordLocation)
+}
+
+// All the violations of a JSON Schema found while validating a value.
+type SchemaErrors []SchemaError
+
+func (e SchemaErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// The maximum depth of nested schemas, which stops infinite reference loops.
+const maxEvalDepth = 256
+
+// A schema and the base URI its references are resolved against.
+type schemaLocation struct {
+	node any
+	base string
+}
+
+// schemaIndex finds schemas by their URIs in a set of generic JSON documents.
+type schemaIndex struct {
+	dialect dialect
+	// Schema resources by their absolute URIs without fragments.
+	resources map[string]any
+	// Schemas by their anchors, like "https://example.com/schema#meta".
+	anchors map[string]schemaLocation
+	// Anchors declared with $dynamicAnchor.
+	dynamicAnchors map[string]bool
+}
+
+func newSchemaIndex(d dialect) *schemaIndex {
+	return &schemaIndex{
+		dialect:        d,
+		resources:      make(map[string]any),
+		anchors:        make(map[string]schemaLocation),
+		dynamicAnchors: make(map[string]bool),
+	}
+}
+
+func (x *schemaIndex) clone() *schemaIndex {
+	return &schemaIndex{
+		dialect:        x.dialect,
+		resources:      maps.Clone(x.resources),
+		anchors:        maps.Clone(x.anchors),
+		dynamicAnchors: maps.Clone(x.dynamicAnchors),
+	}
+}
+
+// add registers the document with the base URI and all the schema resources and anchors in it.
+func (x *schemaIndex) add(base string, root any) {
+	x.resources[base] = root
+	x.walk(base, root)
+}
+
+func (x *schemaIndex) walk(base string, node any) {
+	switch node := node.(type) {
+	case map[string]any:
+		if id := x.baseOf(base, node); id != base {
+			base = id
+			if _, ok := x.resources[base]; !ok {
+				x.resources[base] = node
+			}
+		}
+		if !x.dialect.legacy() {
+			if anchor, ok := node["$anchor"].(string); ok {
+				x.anchors[base+"#"+anchor] = schemaLocation{node, base}
+			}
+			if anchor, ok := node["$dynamicAnchor"].(string); ok {
+				x.anchors[base+"#"+anchor] = schemaLocation{node, base}
+				x.dynamicAnchors[base+"#"+anchor] = true
+			}
+		}
+		for _, child := range node {
+			x.walk(base, child)
+		}
+	case []any:
+		for _, child := range node {
+			x.walk(base, child)
+		}
+	}
+}
+
+// baseOf returns the base URI for the content of the schema, which changes if the schema has an ID.
+func (x *schemaIndex) baseOf(base string, schema map[string]any) string {
+	id, ok := schema[x.dialect.idKeyword()].(string)
+	if !ok || strings.HasPrefix(id, "#") {
+		return base
+	}
+	base, _, _ = strings.Cut(resolveURI(base, id), "#")
+	return base
+}
+
+// resolve finds the schema the reference points to.
+func (x *schemaIndex) resolve(base, ref string) (schemaLocation, bool) {
+	uri := resolveURI(base, ref)
+	res, fragment, _ := strings.Cut(uri, "#")
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		loc, ok := x.anchors[res+"#"+fragment]
+		return loc, ok
+	}
+	root, ok := x.resources[res]
+	if !ok {
+		return schemaLocation{}, false
+	}
+	base = res
+	node, ok := resolvePointer(root, fragment, func(node any) {
+		if schema, ok := node.(map[string]any); ok {
+			base = x.baseOf(base, schema)
+		}
+	})
+	return schemaLocation{node, base}, ok
+}
+
+// resolveURI resolves the URI reference against the base URI.
+func resolveURI(base, ref string) string {
+	if strings.HasPrefix(ref, "#") {
+		base, _, _ = strings.Cut(base, "#")
+		return base + ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || base == "" {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// schemaEvaluator validates generic JSON values against JSON Schemas.
+type schemaEvaluator struct {
+	index *schemaIndex
+	// Checkers for the format keyword. If nil, format is only an annotation.
+	formats Formats
+}
+
+// The position of the evaluation in the schema and in the instance.
+type evalState struct {
+	// The base URI of the current schema resource.
+	base string
+	// The dynamic scope: base URIs of the entered schema resources, the outermost first.
+	scope []string
+	// JSON Pointer to the value in the instance.
+	instance string
+	// JSON Pointer to the keyword in the schema.
+	keyword string
+	depth   int
+}
+
+// keywordAt returns the state for a subschema of the keyword, applied to the same value.
+func (st evalState) keywordAt(tokens ...string) evalState {
+	st.keyword = at(st.keyword, tokens...)
+	return st
+}
+
+// child returns the state for a subschema of the keyword, applied to an item or a property of the value.
+func (st evalState) child(instToken string, tokens ...string) evalState {
+	st.instance = at(st.instance, instToken)
+	st.keyword = at(st.keyword, tokens...)
+	return st
+}
+
+// The outcome of evaluating a schema against a value.
+type evalResult struct {
+	errs SchemaErrors
+	// Names of the properties and indices of the items evaluated by the schema,
+	// used by unevaluatedProperties and unevaluatedItems.
+	props map[string]bool
+	items map[int]bool
+}
+
+func (r *evalResult) valid() bool {
+	return len(r.errs) == 0
+}
+
+func (r *evalResult) fail(st evalState, format string, args ...any) {
+	r.errs = append(r.errs, SchemaError{
+		InstanceLocation: st.instance,
+		KeywordLocation:  st.keyword,
+		Message:          fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *evalResult) evaluatedProp(name string) {
+	if r.props == nil {
+		r.props = make(map[string]bool)
+	}
+	r.props[name] = true
+}
+
+func (r *evalResult) evaluatedItem(i int) {
+	if r.items == nil {
+		r.items = make(map[int]bool)
+	}
+	r.items[i] = true
+}
+
+// addErrors adds the errors of the result for an item or a property of the value.
+func (r *evalResult) addErrors(sub *evalResult) {
+	r.errs = append(r.errs, sub.errs...)
+}
+
+// merge adds the errors of the subschema result and, if it's valid, its annotations.
+func (r *evalResult) merge(sub *evalResult) {
+	r.errs = append(r.errs, sub.errs...)
+	if sub.valid() {
+		r.mergeAnnotations(sub)
+	}
+}
+
+func (r *evalResult) mergeAnnotations(sub *evalResult) {
+	for name := range sub.props {
+		r.evaluatedProp(name)
+	}
+	for i := range sub.items {
+		r.evaluatedItem(i)
+	}
+}
+
+// validate evaluates the schema at the base URI against the value.
+func (e *schemaEvaluator) validate(base string, schema, v any) SchemaErrors {
+	st := evalState{base: base, scope: []string{base}}
+	return e.eval(schema, v, st).errs
+}
+
+func (e *schemaEvaluator) eval(schema, v any, st evalState) *evalResult {
+	r := &evalResult{}
+	if st.depth > maxEvalDepth {
+		r.fail(st, "the schema is nested too deep, it may contain a reference loop")
+		return r
+	}
+	st.depth++
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			r.fail(st, "no value is allowed")
+		}
+	case map[string]any:
+		e.evalObject(s, v, st, r)
+	default:
+		r.fail(st, "invalid schema of type %s", jsonType(schema))
+	}
+	return r
+}
+
+func (e *schemaEvaluator) evalObject(s map[string]any, v any, st evalState, r *evalResult) {
+	d := e.index.dialect
+	if base := e.index.baseOf(st.base, s); base != st.base {
+		st.base = base
+		st.scope = append(slices.Clip(st.scope), base)
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		r.merge(e.evalRef(ref, v, st.keywordAt("$ref")))
+		if d.legacy() {
+			// All other keywords are ignored next to $ref.
+			return
+		}
+	}
+	if ref, ok := s["$dynamicRef"].(string); ok && !d.legacy() {
+		r.merge(e.evalDynamicRef(ref, v, st.keywordAt("$dynamicRef")))
+	}
+	e.evalType(s, v, st, r)
+	e.evalFormat(s, v, st, r)
+	switch v := v.(type) {
+	case json.Number, float64:
+		e.evalNumber(s, v, st, r)
+	case string:
+		e.evalString(s, v, st, r)
+	case []any:
+		e.evalArray(s, v, st, r)
+	case map[string]any:
+		e.evalProperties(s, v, st, r)
+	}
+	e.evalCombinators(s, v, st, r)
+	if !d.legacy() {
+		e.evalUnevaluated(s, v, st, r)
+	}
+}
+
+func (e *schemaEvaluator) evalRef(ref string, v any, st evalState) *evalResult {
+	loc, ok := e.index.resolve(st.base, ref)
+	if !ok {
+		r := &evalResult{}
+		r.fail(st, "cannot resolve the reference %q", ref)
+		return r
+	}
+	st.base = loc.base
+	return e.eval(loc.node, v, st)
+}
+
+// evalDynamicRef resolves the reference in the dynamic scope if it points to a $dynamicAnchor.
+func (e *schemaEvaluator) evalDynamicRef(ref string, v any, st evalState) *evalResult {
+	uri := resolveURI(st.base, ref)
+	_, anchor, _ := strings.Cut(uri, "#")
+	if e.index.dynamicAnchors[uri] {
+		for _, base := range st.scope {
+			if e.index.dynamicAnchors[base+"#"+anchor] {
+				ref = base + "#" + anchor
+				break
+			}
+		}
+	}
+	return e.evalRef(ref, v, st)
+}
+
+func (e *schemaEvaluator) evalType(s map[string]any, v any, st evalState, r *evalResult) {
+	if want, ok := s["enum"].([]any); ok {
+		if !slices.ContainsFunc(want, func(item any) bool { return equalJSON(item, v) }) {
+			r.fail(st.keywordAt("enum"), "the value must be one of %s", formatJSON(want))
+		}
+	}
+	if want, ok := s["const"]; ok && !e.index.dialect.legacy() {
+		if !equalJSON(want, v) {
+			r.fail(st.keywordAt("const"), "the value must be %s", formatJSON(want))
+		}
+	}
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	default:
+		return
+	}
+	if e.index.dialect == dialectOAS30 && s["nullable"] == true {
+		types = append(types, TypeNull)
+	}
+	for _, t := range types {
+		if hasType(v, t) {
+			return
+		}
+	}
+	r.fail(st.keywordAt("type"), "expected %s, got %s", strings.Join(types, " or "), jsonType(v))
+}
+
+// evalFormat asserts the format if there is a checker for it. Unknown formats are ignored.
+func (e *schemaEvaluator) evalFormat(s map[string]any, v any, st evalState, r *evalResult) {
+	name, ok := s["format"].(string)
+	if !ok {
+		return
+	}
+	check := e.formats[name]
+	if check == nil {
+		return
+	}
+	err := check(v)
+	if err != nil {
+		r.fail(st.keywordAt("format"), "the value doesn't match the format %q: %v", name, err)
+	}
+}
+
+func (e *schemaEvaluator) evalNumber(s map[string]any, v any, st evalState, r *evalResult) {
+	n, _ := toFloat(v)
+	if m, ok := toFloat(s["multipleOf"]); ok && m > 0 {
+		q := n / m
+		if math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9*math.Max(1, math.Abs(q)) {
+			r.fail(st.keywordAt("multipleOf"), "%v is not a multiple of %v", v, s["multipleOf"])
+		}
+	}
+	if e.index.dialect.legacy() {
+		// The exclusive keywords are booleans that modify maximum and minimum.
+		if max, ok := toFloat(s["maximum"]); ok {
+			if s["exclusiveMaximum"] == true && n >= max {
+				r.fail(st.keywordAt("exclusiveMaximum"), "%v is not less than %v", v, s["maximum"])
+			} else if n > max {
+				r.fail(st.keywordAt("maximum"), "%v is greater than the maximum %v", v, s["maximum"])
+			}
+		}
+		if min, ok := toFloat(s["minimum"]); ok {
+			if s["exclusiveMinimum"] == true && n <= min {
+				r.fail(st.keywordAt("exclusiveMinimum"), "%v is not greater than %v", v, s["minimum"])
+			} else if n < min {
+				r.fail(st.keywordAt("minimum"), "%v is less than the minimum %v", v, s["minimum"])
+			}
+		}
+		return
+	}
+	if max, ok := toFloat(s["maximum"]); ok && n > max {
+		r.fail(st.keywordAt("maximum"), "%v is greater than the maximum %v", v, s["maximum"])
+	}
+	if max, ok := toFloat(s["exclusiveMaximum"]); ok && n >= max {
+		r.fail(st.keywordAt("exclusiveMaximum"), "%v is not less than %v", v, s["exclusiveMaximum"])
+	}
+	if min, ok := toFloat(s["minimum"]); ok && n < min {
+		r.fail(st.keywordAt("minimum"), "%v is less than the minimum %v", v, s["minimum"])
+	}
+	if min, ok := toFloat(s["exclusiveMinimum"]); ok && n <= min {
+		r.fail(st.keywordAt("exclusiveMinimum"), "%v is not greater than %v", v, s["exclusiveMinimum"])
+	}
+}
+
+func (e *schemaEvaluator) evalString(s map[string]any, v string, st evalState, r *evalResult) {
+	length := utf8.RuneCountInString(v)
+	if max, ok := toInt(s["maxLength"]); ok && length > max {
+		r.fail(st.keywordAt("maxLength"), "the string is longer than %d characters", max)
+	}
+	if min, ok := toInt(s["minLength"]); ok && length < min {
+		r.fail(st.keywordAt("minLength"), "the string is shorter than %d characters", min)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			r.fail(st.keywordAt("pattern"), "invalid pattern: %v", err)
+		} else if !re.MatchString(v) {
+			r.fail(st.keywordAt("pattern"), "the string doesn't match the pattern %q", pattern)
+		}
+	}
+}
+
+func (e *schemaEvaluator) evalArray(s map[string]any, v []any, st evalState, r *evalResult) {
+	if max, ok := toInt(s["maxItems"]); ok && len(v) > max {
+		r.fail(st.keywordAt("maxItems"), "the array has more than %d items", max)
+	}
+	if min, ok := toInt(s["minItems"]); ok && len(v) < min {
+		r.fail(st.keywordAt("minItems"), "the array has fewer than %d items", min)
+	}
+	if s["uniqueItems"] == true {
+	unique:
+		for i := range v {
+			for j := range i {
+				if equalJSON(v[i], v[j]) {
+					r.fail(st.keywordAt("uniqueItems"), "the items %d and %d are equal", j, i)
+					break unique
+				}
+			}
+		}
+	}
+
+	// The tuple keyword applies to the first items and the rest keyword to the remaining ones.
+	tuple, rest := "prefixItems", "items"
+	if e.index.dialect.legacy() {
+		tuple, rest = "items", "additionalItems"
+	}
+	start := 0
+	if prefix, ok := s[tuple].([]any); ok {
+		for i, sub := range prefix {
+			if i >= len(v) {
+				break
+			}
+			r.addErrors(e.eval(sub, v[i], st.child(strconv.Itoa(i), tuple, strconv.Itoa(i))))
+			r.evaluatedItem(i)
+		}
+		start = len(prefix)
+	} else if e.index.dialect.legacy() {
+		rest = "items"
+	}
+	if sub, ok := s[rest]; ok {
+		for i := start; i < len(v); i++ {
+			sr := e.eval(sub, v[i], st.child(strconv.Itoa(i), rest))
+			if sub == false {
+				sr = &evalResult{}
+				sr.fail(st.child(strconv.Itoa(i), rest), "additional item %d is not allowed", i)
+			}
+			r.addErrors(sr)
+			r.evaluatedItem(i)
+		}
+	}
+
+	if sub, ok := s["contains"]; ok && !e.index.dialect.legacy() {
+		matches := 0
+		for i, item := range v {
+			if e.eval(sub, item, st.child(strconv.Itoa(i), "contains")).valid() {
+				matches++
+				r.evaluatedItem(i)
+			}
+		}
+		min, ok := toInt(s["minContains"])
+		if !ok {
+			min = 1
+		}
+		if matches < min {
+			if min == 1 {
+				r.fail(st.keywordAt("contains"), "the array doesn't contain a matching item")
+			} else {
+				r.fail(st.keywordAt("minContains"), "the array contains fewer than %d matching items", min)
+			}
+		}
+		if max, ok := toInt(s["maxContains"]); ok && matches > max {
+			r.fail(st.keywordAt("maxContains"), "the array contains more than %d matching items", max)
+		}
+	}
+}
+
+func (e *schemaEvaluator) evalProperties(s map[string]any, v map[string]any, st evalState, r *evalResult) {
+	if max, ok := toInt(s["maxProperties"]); ok && len(v) > max {
+		r.fail(st.keywordAt("maxProperties"), "the object has more than %d properties", max)
+	}
+	if min, ok := toInt(s["minProperties"]); ok && len(v) < min {
+		r.fail(st.keywordAt("minProperties"), "the object has fewer than %d properties", min)
+	}
+	if required, ok := s["required"].([]any); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, ok := v[name]; !ok {
+					r.fail(st.keywordAt("required"), "missing required property %q", name)
+				}
+			}
+		}
+	}
+	names := sortedKeys(v)
+
+	// Keywords that apply depending on the presence of properties.
+	dependents := map[string]any{}
+	if e.index.dialect.legacy() {
+		if deps, ok := s["dependencies"].(map[string]any); ok {
+			dependents = deps
+		}
+	} else {
+		if deps, ok := s["dependentRequired"].(map[string]any); ok {
+			dependents = deps
+		}
+		if deps, ok := s["dependentSchemas"].(map[string]any); ok {
+			for _, name := range sortedKeys(deps) {
+				if _, ok := v[name]; ok {
+					r.merge(e.eval(deps[name], v, st.keywordAt("dependentSchemas", name)))
+				}
+			}
+		}
+	}
+	for _, name := range sortedKeys(dependents) {
+		if _, ok := v[name]; !ok {
+			continue
+		}
+		keyword := "dependentRequired"
+		if e.index.dialect.legacy() {
+			keyword = "dependencies"
+		}
+		required, ok := dependents[name].([]any)
+		if !ok {
+			r.merge(e.eval(dependents[name], v, st.keywordAt(keyword, name)))
+			continue
+		}
+		for _, other := range required {
+			if other, ok := other.(string); ok {
+				if _, ok := v[other]; !ok {
+					r.fail(st.keywordAt(keyword, name), "property %q is required when %q is present", other, name)
+				}
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]any)
+	patterns, _ := s["patternProperties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+	for _, name := range names {
+		matched := false
+		if sub, ok := props[name]; ok {
+			matched = true
+			r.addErrors(e.eval(sub, v[name], st.child(name, "properties", name)))
+		}
+		for _, pattern := range sortedKeys(patterns) {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				r.fail(st.keywordAt("patternProperties", pattern), "invalid pattern: %v", err)
+				continue
+			}
+			if re.MatchString(name) {
+				matched = true
+				r.addErrors(e.eval(patterns[pattern], v[name], st.child(name, "patternProperties", pattern)))
+			}
+		}
+		if !matched && hasAdditional {
+			matched = true
+			r.addErrors(e.evalProperty(additional, name, v[name], st, "additionalProperties", "additional"))
+		}
+		if matched {
+			r.evaluatedProp(name)
+		}
+	}
+
+	if sub, ok := s["propertyNames"]; ok {
+		for _, name := range names {
+			r.addErrors(e.eval(sub, name, st.child(name, "propertyNames")))
+		}
+	}
+}
+
+// evalProperty evaluates a property against the schema of additionalProperties or unevaluatedProperties.
+func (e *schemaEvaluator) evalProperty(schema any, name string, v any, st evalState, keyword, kind string) *evalResult {
+	st = st.child(name, keyword)
+	if schema == false {
+		r := &evalResult{}
+		r.fail(st, "%s property %q is not allowed", kind, name)
+		return r
+	}
+	return e.eval(schema, v, st)
+}
+
+func (e *schemaEvaluator) evalCombinators(s map[string]any, v any, st evalState, r *evalResult) {
+	if subs, ok := s["allOf"].([]any); ok {
+		for i, sub := range subs {
+			r.merge(e.eval(sub, v, st.keywordAt("allOf", strconv.Itoa(i))))
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		subs, ok := s[keyword].([]any)
+		if !ok {
+			continue
+		}
+		var matched []int
+		var errs SchemaErrors
+		for i, sub := range subs {
+			sr := e.eval(sub, v, st.keywordAt(keyword, strconv.Itoa(i)))
+			if sr.valid() {
+				matched = append(matched, i)
+				r.mergeAnnotations(sr)
+			}
+			errs = append(errs, sr.errs...)
+		}
+		switch {
+		case len(matched) == 0:
+			r.fail(st.keywordAt(keyword), "the value doesn't match any of the schemas")
+			r.errs = append(r.errs, errs...)
+		case keyword == "oneOf" && len(matched) > 1:
+			r.fail(st.keywordAt(keyword), "the value matches more than one schema: %d and %d", matched[0], matched[1])
+		}
+	}
+	if sub, ok := s["not"]; ok {
+		if e.eval(sub, v, st.keywordAt("not")).valid() {
+			r.fail(st.keywordAt("not"), "the value must not match the schema")
+		}
+	}
+	if sub, ok := s["if"]; ok && !e.index.dialect.legacy() {
+		cond := e.eval(sub, v, st.keywordAt("if"))
+		if cond.valid() {
+			r.mergeAnnotations(cond)
+			if then, ok := s["then"]; ok {
+				r.merge(e.eval(then, v, st.keywordAt("then")))
+			}
+		} else if els, ok := s["else"]; ok {
+			r.merge(e.eval(els, v, st.keywordAt("else")))
+		}
+	}
+}
+
+// evalUnevaluated applies unevaluatedItems and unevaluatedProperties,
+// which must go after all other keywords.
+func (e *schemaEvaluator) evalUnevaluated(s map[string]any, v any, st evalState, r *evalResult) {
+	switch v := v.(type) {
+	case []any:
+		sub, ok := s["unevaluatedItems"]
+		if !ok {
+			return
+		}
+		for i, item := range v {
+			if r.items[i] {
+				continue
+			}
+			sr := e.eval(sub, item, st.child(strconv.Itoa(i), "unevaluatedItems"))
+			if sub == false {
+				sr = &evalResult{}
+				sr.fail(st.child(strconv.Itoa(i), "unevaluatedItems"), "unevaluated item %d is not allowed", i)
+			}
+			r.addErrors(sr)
+			r.evaluatedItem(i)
+		}
+	case map[string]any:
+		sub, ok := s["unevaluatedProperties"]
+		if !ok {
+			return
+		}
+		for _, name := range sortedKeys(v) {
+			if r.props[name] {
+				continue
+			}
+			r.addErrors(e.evalProperty(sub, name, v[name], st, "unevaluatedProperties", "unevaluated"))
+			r.evaluatedProp(name)
+		}
+	}
+}
+
+// jsonType returns the JSON type name of the generic JSON value.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func hasType(v any, t string) bool {
+	switch t {
+	case TypeInteger:
+		n, ok := toFloat(v)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case TypeNumber:
+		_, ok := toFloat(v)
+		return ok
+	}
+	return jsonType(v) == t
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil || math.IsInf(n, 0)
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toInt(v any) (int, bool) {
+	n, ok := toFloat(v)
+	if !ok || n != math.Trunc(n) {
+		return 0, false
+	}
+	return int(min(n, math.MaxInt32)), true
+}
+
+// equalJSON reports if the generic JSON values are equal, comparing numbers by their values.
+func equalJSON(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number, float64:
+		x, _ := toFloat(a)
+		y, ok := toFloat(b)
+		return ok && x == y
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, equalJSON)
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, val := range a {
+			other, ok := b[key]
+			if !ok || !equalJSON(val, other) {
+				return false
+			}
+		}
+		return true
+	}
+	switch b.(type) {
+	case json.Number, float64, []any, map[string]any:
+		return false
+	}
+	return a == b
+}
+
+func formatJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+var patterns sync.Map
+
+// compilePattern compiles the regular expression of the pattern keyword, caching the result.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,405 @@
+package openapi
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Algorithms of the keys supported by [JWTVerifier].
+const (
+	// HMAC with SHA-256. The key is []byte of at least 32 bytes.
+	JWTAlgHS256 = "HS256"
+	// RSASSA-PKCS1-v1_5 with SHA-256. The key is *rsa.PublicKey.
+	JWTAlgRS256 = "RS256"
+	// ECDSA with the P-256 curve and SHA-256. The key is *ecdsa.PublicKey.
+	JWTAlgES256 = "ES256"
+	// Ed25519 signatures. The key is ed25519.PublicKey.
+	JWTAlgEdDSA = "EdDSA"
+)
+
+// JWTKey is a key to verify the signatures of JSON Web Tokens.
+type JWTKey struct {
+	// The key ID. If both the key and the token have IDs, they must be equal.
+	ID string
+	// The algorithm of the signatures, like [JWTAlgRS256]. Tokens signed with other algorithms are rejected.
+	Algorithm string
+	// The key of the type required by the algorithm.
+	Key any
+}
+
+// JWTVerifier verifies JSON Web Tokens (RFC 7519) signed with local keys.
+//
+// The token must be signed by one of the keys and must not be expired.
+// The "exp" and "nbf" claims are checked if present, and "iss" and "aud" if configured.
+type JWTVerifier struct {
+	// The keys to verify the signatures, like the ones from [LoadJWKS].
+	Keys []JWTKey
+	// If not empty, the "iss" claim must be equal to it.
+	Issuer string
+	// If not empty, the "aud" claim must contain it.
+	Audience string
+	// The allowed clock skew for "exp" and "nbf".
+	Leeway time.Duration
+	// The current time. If nil, [time.Now] is used.
+	Now func() time.Time
+}
+
+// VerifyJWT returns a middleware that authenticates requests with JSON Web Tokens
+// by the security requirements of their operations, like [EnforceSecurity] does.
+//
+// The verifiers are keyed by the names of the security schemes. The schemes must be
+// "http" with the "bearer" scheme, "oauth2", or "openIdConnect", and the token is taken
+// from the Authorization header. The principal has the claims of the token as the identity
+// and the scopes from the "scope" or "scp" claim. If the document declares scopes in the flows
+// of OAuth 2.0 schemes, only the declared ones are granted.
+//
+// All the schemes used by the security requirements must have a verifier. To combine the tokens
+// with other schemes, use [JWTVerifier.Authenticator] with [EnforceSecurity].
+// It panics if a scheme isn't defined or doesn't carry bearer tokens.
+func VerifyJWT(doc *OpenAPI, verifiers map[string]*JWTVerifier) func(http.Handler) http.Handler {
+	authenticators := make(map[string]Authenticator, len(verifiers))
+	for _, name := range sortedKeys(verifiers) {
+		ref, ok := doc.Components.SecuritySchemes[name]
+		if !ok {
+			panic(fmt.Sprintf("openapi: verify JWT: the security scheme %q is not defined", name))
+		}
+		scheme, err := ref.Resolve(doc)
+		if err != nil {
+			panic(fmt.Sprintf("openapi: verify JWT: security scheme %s: %v", name, err))
+		}
+		bearer := scheme.Type == SecurityTypeOAuth2 || scheme.Type == SecurityTypeOpenIDConnect ||
+			scheme.Type == SecurityTypeHTTP && strings.EqualFold(scheme.Scheme, "bearer")
+		if !bearer {
+			panic(fmt.Sprintf("openapi: verify JWT: the security scheme %q doesn't use bearer tokens", name))
+		}
+		authenticators[name] = verifiers[name].Authenticator(doc)
+	}
+	return EnforceSecurity(doc, SecurityEnforcement{Authenticators: authenticators})
+}
+
+// Authenticator returns an [Authenticator] for the bearer tokens of a security scheme.
+//
+// The principal has the claims of the token as the identity and the scopes from the "scope"
+// or "scp" claim. Only the scopes declared by the security scheme are granted, if it's
+// an OAuth 2.0 scheme, or the scopes declared by all OAuth 2.0 schemes of the document otherwise.
+// If there are no declared scopes, all the scopes of the token are granted.
+func (v *JWTVerifier) Authenticator(doc *OpenAPI) Authenticator {
+	// The scopes declared by all OAuth 2.0 schemes, for the schemes of other types.
+	declared := make(map[string]string)
+	for _, ref := range doc.Components.SecuritySchemes {
+		scheme, err := ref.Resolve(doc)
+		if err == nil && scheme.Type == SecurityTypeOAuth2 {
+			maps.Copy(declared, scheme.Flows.scopes())
+		}
+	}
+	return func(r *http.Request, c Credentials) (Principal, error) {
+		claims, err := v.Verify(c.Token)
+		if err != nil {
+			return Principal{}, err
+		}
+		known := declared
+		if c.SecurityScheme.Type == SecurityTypeOAuth2 {
+			known = c.SecurityScheme.Flows.scopes()
+		}
+		var scopes []string
+		for _, scope := range tokenScopes(claims) {
+			if _, ok := known[scope]; ok || len(known) == 0 {
+				scopes = append(scopes, scope)
+			}
+		}
+		return Principal{Identity: claims, Scopes: scopes}, nil
+	}
+}
+
+// tokenScopes returns the scopes from the "scope" claim, a space-separated string,
+// or from the "scp" claim, either a string or an array of strings.
+func tokenScopes(claims map[string]any) []string {
+	scope := claims["scope"]
+	if scope == nil {
+		scope = claims["scp"]
+	}
+	switch scope := scope.(type) {
+	case string:
+		return strings.Fields(scope)
+	case []any:
+		var scopes []string
+		for _, s := range scope {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+// Verify checks the signature and the claims of the token and returns the claims.
+func (v *JWTVerifier) Verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("the token must have three parts")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err := decodeJWTPart(parts[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("invalid token signature encoding")
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range v.Keys {
+		if key.Algorithm != header.Alg || key.ID != "" && header.Kid != "" && key.ID != header.Kid {
+			continue
+		}
+		if verifyJWTSignature(key, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("invalid token signature")
+	}
+	var claims map[string]any
+	err = decodeJWTPart(parts[1], &claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	err = v.checkClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// decodeJWTPart decodes the base64url-encoded JSON object.
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.New("invalid base64url encoding")
+	}
+	return decodeJSON(data, v)
+}
+
+// minHMACKeySize is the minimal size of HS256 keys, the size of the hash output (RFC 7518, Section 3.2).
+// Shorter keys, including empty ones, are rejected.
+const minHMACKeySize = 32
+
+// verifyJWTSignature reports if the signature of the signed data is valid for the key.
+func verifyJWTSignature(key JWTKey, signed, sig []byte) bool {
+	digest := sha256.Sum256(signed)
+	switch key.Algorithm {
+	case JWTAlgHS256:
+		secret, ok := key.Key.([]byte)
+		if !ok || len(secret) < minHMACKeySize {
+			return false
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), sig)
+	case JWTAlgRS256:
+		pub, ok := key.Key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+	case JWTAlgES256:
+		pub, ok := key.Key.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, digest[:], r, s)
+	case JWTAlgEdDSA:
+		pub, ok := key.Key.(ed25519.PublicKey)
+		return ok && len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, signed, sig)
+	}
+	return false
+}
+
+// checkClaims checks the registered claims of a verified token.
+func (v *JWTVerifier) checkClaims(claims map[string]any) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if exp, ok := claims["exp"]; ok {
+		t, ok := numericDate(exp)
+		if !ok {
+			return errors.New("invalid exp claim")
+		}
+		if !now.Before(t.Add(v.Leeway)) {
+			return errors.New("the token is expired")
+		}
+	}
+	if nbf, ok := claims["nbf"]; ok {
+		t, ok := numericDate(nbf)
+		if !ok {
+			return errors.New("invalid nbf claim")
+		}
+		if now.Add(v.Leeway).Before(t) {
+			return errors.New("the token is not valid yet")
+		}
+	}
+	if v.Issuer != "" && claims["iss"] != v.Issuer {
+		return fmt.Errorf("the token issuer must be %q", v.Issuer)
+	}
+	if v.Audience != "" {
+		var audience []any
+		switch aud := claims["aud"].(type) {
+		case string:
+			audience = []any{aud}
+		case []any:
+			audience = aud
+		}
+		if !slices.Contains(audience, any(v.Audience)) {
+			return fmt.Errorf("the token audience must include %q", v.Audience)
+		}
+	}
+	return nil
+}
+
+// maxNumericDate is the largest number of seconds since the epoch accepted in claims.
+// Larger numbers can't be represented exactly by float64, and are far beyond
+// the dates time.Time can be shifted by a leeway without overflowing.
+const maxNumericDate = 1 << 53
+
+// numericDate converts the seconds since the epoch into time.
+func numericDate(v any) (time.Time, bool) {
+	seconds, ok := toFloat(v)
+	if !ok || math.IsNaN(seconds) || math.Abs(seconds) > maxNumericDate {
+		return time.Time{}, false
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*float64(time.Second))), true
+}
+
+// LoadJWKS reads the keys to verify tokens from a JSON Web Key Set file (RFC 7517).
+// See [ParseJWKS].
+func LoadJWKS(path string) ([]JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses the keys to verify tokens from a JSON Web Key Set (RFC 7517).
+//
+// Supported are "oct" keys for HS256, "RSA" keys for RS256, "EC" keys with the "P-256" curve
+// for ES256, and "OKP" keys with the "Ed25519" curve for EdDSA. Keys of other types
+// and keys for encryption are skipped. Private parts of the keys are ignored.
+func ParseJWKS(data []byte) ([]JWTKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			K   string `json:"k"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	err := json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	var keys []JWTKey
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key := JWTKey{ID: jwk.Kid}
+		switch {
+		case jwk.Kty == "oct":
+			key.Algorithm = JWTAlgHS256
+			var secret []byte
+			secret, err = base64.RawURLEncoding.DecodeString(jwk.K)
+			if err == nil && len(secret) < minHMACKeySize {
+				err = fmt.Errorf("the HS256 key must be at least %d bytes", minHMACKeySize)
+			}
+			key.Key = secret
+		case jwk.Kty == "RSA":
+			key.Algorithm = JWTAlgRS256
+			key.Key, err = rsaPublicKey(jwk.N, jwk.E)
+		case jwk.Kty == "EC" && jwk.Crv == "P-256":
+			key.Algorithm = JWTAlgES256
+			key.Key, err = ecdsaPublicKey(jwk.X, jwk.Y)
+		case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+			key.Algorithm = JWTAlgEdDSA
+			var pub []byte
+			pub, err = base64.RawURLEncoding.DecodeString(jwk.X)
+			if err == nil && len(pub) != ed25519.PublicKeySize {
+				err = errors.New("invalid Ed25519 key size")
+			}
+			key.Key = ed25519.PublicKey(pub)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %d: %w", i, err)
+		}
+		if jwk.Alg != "" && jwk.Alg != key.Algorithm {
+			// The key is meant for an unsupported algorithm, like RS512.
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func rsaPublicKey(n, e string) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, errors.New("invalid RSA modulus")
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil || len(eb) == 0 || len(eb) > 4 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	exp := new(big.Int).SetBytes(eb)
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(exp.Int64())}, nil
+}
+
+func ecdsaPublicKey(x, y string) (*ecdsa.PublicKey, error) {
+	xb, err1 := base64.RawURLEncoding.DecodeString(x)
+	yb, err2 := base64.RawURLEncoding.DecodeString(y)
+	if err1 != nil || err2 != nil || len(xb) != 32 || len(yb) != 32 {
+		return nil, errors.New("invalid P-256 coordinates")
+	}
+	// crypto/ecdh checks that the point is on the curve.
+	_, err := ecdh.P256().NewPublicKey(slices.Concat([]byte{4}, xb, yb))
+	if err != nil {
+		return nil, errors.New("invalid P-256 point")
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(xb),
+		Y:     new(big.Int).SetBytes(yb),
+	}, nil
+}
@@ -0,0 +1,265 @@
+package openapi_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+// signJWT creates a token with the claims signed by the private key for the algorithm.
+func signJWT(t *testing.T, alg, kid string, key any, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	var sig []byte
+	var err error
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key, digest[:])
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(key, []byte(signed))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + enc.EncodeToString(sig)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	secret := []byte("a secret of at least thirty-two bytes")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	// The public key as an HMAC secret.
+	rsaPublic, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	now := time.Unix(1_700_000_000, 0)
+	v := &openapi.JWTVerifier{
+		Keys: []openapi.JWTKey{
+			{Algorithm: openapi.JWTAlgHS256, Key: secret},
+			{ID: "rsa", Algorithm: openapi.JWTAlgRS256, Key: &rsaKey.PublicKey},
+			{Algorithm: openapi.JWTAlgES256, Key: &ecKey.PublicKey},
+			{Algorithm: openapi.JWTAlgEdDSA, Key: edPub},
+		},
+		Issuer:   "https://issuer.example.com",
+		Audience: "pets",
+		Leeway:   time.Minute,
+		Now:      func() time.Time { return now },
+	}
+	valid := func(extra map[string]any) map[string]any {
+		claims := map[string]any{
+			"sub": "alice",
+			"iss": "https://issuer.example.com",
+			"aud": []string{"pets", "users"},
+			"exp": now.Add(time.Hour).Unix(),
+			"nbf": now.Add(-time.Hour).Unix(),
+		}
+		for key, value := range extra {
+			claims[key] = value
+		}
+		return claims
+	}
+	tests := []struct {
+		name  string
+		token string
+		// The expected error, or empty if the token is valid.
+		err string
+	}{
+		{"HS256", signJWT(t, "HS256", "", secret, valid(nil)), ""},
+		{"RS256", signJWT(t, "RS256", "rsa", rsaKey, valid(nil)), ""},
+		{"ES256", signJWT(t, "ES256", "", ecKey, valid(nil)), ""},
+		{"EdDSA", signJWT(t, "EdDSA", "", edKey, valid(nil)), ""},
+		{"single audience", signJWT(t, "HS256", "", secret, valid(map[string]any{"aud": "pets"})), ""},
+		{"within leeway", signJWT(t, "HS256", "", secret, valid(map[string]any{"exp": now.Add(-30 * time.Second).Unix()})), ""},
+		{"after 2262", signJWT(t, "HS256", "", secret, valid(map[string]any{"exp": 10_000_000_000})), ""},
+		{"fractional exp", signJWT(t, "HS256", "", secret, valid(map[string]any{"exp": float64(now.Unix()) + 0.5})), ""},
+		{"huge exp", signJWT(t, "HS256", "", secret, valid(map[string]any{"exp": 1e300})), "invalid exp claim"},
+		{"wrong key", signJWT(t, "HS256", "", []byte("guess"), valid(nil)), "invalid token signature"},
+		{"wrong key ID", signJWT(t, "RS256", "other", rsaKey, valid(nil)), "invalid token signature"},
+		{"algorithm confusion", signJWT(t, "HS256", "rsa", rsaPublic, valid(nil)), "invalid token signature"},
+		{"invalid header", "e30=.e30.", "invalid token header: invalid base64url encoding"},
+		{"none", signJWT(t, "none", "", []byte{}, valid(nil)), "invalid token signature"},
+		{"expired", signJWT(t, "HS256", "", secret, valid(map[string]any{"exp": now.Add(-time.Hour).Unix()})), "the token is expired"},
+		{"not yet valid", signJWT(t, "HS256", "", secret, valid(map[string]any{"nbf": now.Add(time.Hour).Unix()})), "the token is not valid yet"},
+		{"wrong issuer", signJWT(t, "HS256", "", secret, valid(map[string]any{"iss": "https://evil.example.com"})), `the token issuer must be "https://issuer.example.com"`},
+		{"wrong audience", signJWT(t, "HS256", "", secret, valid(map[string]any{"aud": "users"})), `the token audience must include "pets"`},
+		{"malformed", "abc", "the token must have three parts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(tt.token)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if claims["sub"] != "alice" {
+					t.Errorf("unexpected claims: %v", claims)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	for _, key := range [][]byte{nil, []byte("secret")} {
+		v := &openapi.JWTVerifier{Keys: []openapi.JWTKey{{Algorithm: openapi.JWTAlgHS256, Key: key}}}
+		_, err := v.Verify(signJWT(t, "HS256", "", key, valid(nil)))
+		if err == nil {
+			t.Errorf("expected an error for the %d bytes key", len(key))
+		}
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	enc := base64.RawURLEncoding
+	ecPoint, _ := ecKey.PublicKey.ECDH()
+	point := ecPoint.Bytes()
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "hmac", "k": %q},
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "use": "sig", "n": %q, "e": "AQAB"},
+		{"kty": "RSA", "kid": "rsa-enc", "use": "enc", "n": %q, "e": "AQAB"},
+		{"kty": "RSA", "kid": "rsa512", "alg": "RS512", "n": %q, "e": "AQAB"},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": %q},
+		{"kty": "OKP", "kid": "x", "crv": "X25519", "x": "AAAA"}
+	]}`,
+		enc.EncodeToString([]byte("a secret of at least thirty-two bytes")),
+		enc.EncodeToString(rsaKey.N.Bytes()),
+		enc.EncodeToString(rsaKey.N.Bytes()),
+		enc.EncodeToString(rsaKey.N.Bytes()),
+		enc.EncodeToString(point[1:33]), enc.EncodeToString(point[33:]),
+		enc.EncodeToString(edPub),
+	)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	err := os.WriteFile(path, []byte(jwks), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := openapi.LoadJWKS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, key := range keys {
+		ids = append(ids, key.ID+":"+key.Algorithm)
+	}
+	want := []string{"hmac:HS256", "rsa:RS256", "ec:ES256", "ed:EdDSA"}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("got keys %v, want %v", ids, want)
+	}
+
+	v := &openapi.JWTVerifier{Keys: keys}
+	claims := map[string]any{"sub": "alice"}
+	for _, token := range []string{
+		signJWT(t, "HS256", "hmac", []byte("a secret of at least thirty-two bytes"), claims),
+		signJWT(t, "RS256", "rsa", rsaKey, claims),
+		signJWT(t, "ES256", "ec", ecKey, claims),
+		signJWT(t, "EdDSA", "ed", edKey, claims),
+	} {
+		_, err := v.Verify(token)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	_, err = openapi.ParseJWKS([]byte(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AAAA", "y": "AAAA"}]}`))
+	if err == nil {
+		t.Error("expected an error for an invalid EC key")
+	}
+	_, err = openapi.ParseJWKS([]byte(`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`))
+	if err == nil {
+		t.Error("expected an error for a short HS256 key")
+	}
+}
+
+func TestVerifyJWT(t *testing.T) {
+	secret := []byte("a secret of at least thirty-two bytes")
+	doc := securedAPI()
+	doc.Components.SecuritySchemes["jwt"] = openapi.Inline(openapi.HTTPBearerScheme("JWT"))
+	doc.Security = []openapi.SecurityRequirement{{"jwt": {}}}
+	doc.Paths.Items["/pets"] = openapi.PathItem{
+		Get: openapi.Operation{
+			Security:  []openapi.SecurityRequirement{{"oauth": {"read"}}},
+			Responses: openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "OK"})},
+		},
+		Post: openapi.Operation{
+			Security:  []openapi.SecurityRequirement{{"jwt": {"write"}}},
+			Responses: openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "OK"})},
+		},
+	}
+	doc.Paths.Items["/admin"] = openapi.PathItem{}
+	verifier := &openapi.JWTVerifier{Keys: []openapi.JWTKey{{Algorithm: openapi.JWTAlgHS256, Key: secret}}}
+	handler := openapi.VerifyJWT(doc, map[string]*openapi.JWTVerifier{"jwt": verifier, "oauth": verifier})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, p := range openapi.Principals(r.Context()) {
+				claims := p.Identity.(map[string]any)
+				fmt.Fprintf(w, "%s:%s:%v", p.Scheme, claims["sub"], p.Scopes)
+			}
+		}),
+	)
+	tests := []struct {
+		method string
+		path   string
+		claims map[string]any
+		status int
+		body   string
+	}{
+		{"GET", "/pets/1", map[string]any{"sub": "alice"}, http.StatusOK, "jwt:alice:[]"},
+		{"GET", "/pets/1", nil, http.StatusUnauthorized, ""},
+		{"GET", "/pets", map[string]any{"sub": "alice", "scope": "read admin"}, http.StatusOK, "oauth:alice:[read]"},
+		{"GET", "/pets", map[string]any{"sub": "alice", "scp": []string{"write"}}, http.StatusForbidden, ""},
+		// Only the scopes declared by the OAuth 2.0 schemes are granted.
+		{"POST", "/pets", map[string]any{"sub": "alice", "scp": "read write admin"}, http.StatusOK, "jwt:alice:[read write]"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.claims != nil {
+			r.Header.Set("Authorization", "Bearer "+signJWT(t, "HS256", "", secret, tt.claims))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("%s %s: got status %d, want %d: %s", tt.method, tt.path, w.Code, tt.status, w.Body)
+			continue
+		}
+		if tt.status == http.StatusOK && w.Body.String() != tt.body {
+			t.Errorf("%s %s: got %q, want %q", tt.method, tt.path, w.Body, tt.body)
+		}
+	}
+}
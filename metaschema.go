@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The official JSON Schema for OpenAPI 3.0 documents.
+//
+//go:embed schemas/oas-3.0.json
+var oas30MetaSchema []byte
+
+// The official JSON Schema for OpenAPI 3.1 documents, without validation of the Schema Objects.
+//
+//go:embed schemas/oas-3.1.json
+var oas31MetaSchema []byte
+
+// A parsed meta-schema ready for evaluation.
+type metaSchema struct {
+	id        string
+	root      any
+	evaluator *schemaEvaluator
+}
+
+// Meta-schemas by the major and minor OpenAPI version they describe.
+var metaSchemas = map[string]func() *metaSchema{
+	"3.0": sync.OnceValue(func() *metaSchema { return loadMetaSchema(dialectDraft4, oas30MetaSchema) }),
+	"3.1": sync.OnceValue(func() *metaSchema { return loadMetaSchema(dialectDraft2020, oas31MetaSchema) }),
+}
+
+func loadMetaSchema(d dialect, data []byte) *metaSchema {
+	var root map[string]any
+	err := decodeJSON(data, &root)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded meta-schema: %v", err))
+	}
+	id := root[d.idKeyword()].(string)
+	index := newSchemaIndex(d)
+	index.add(id, root)
+	return &metaSchema{id: id, root: root, evaluator: &schemaEvaluator{index: index}}
+}
+
+// ValidateDocument checks the raw OpenAPI document against the official JSON Schema
+// of its OpenAPI version. The document can be in JSON or YAML.
+//
+// Unlike [Parse], it checks the document exactly as written, including unknown fields.
+// The supported versions are 3.0.x and 3.1.x. If the document is invalid,
+// the returned error is [SchemaErrors].
+func ValidateDocument(data []byte) error {
+	var node yaml.Node
+	err := yaml.Unmarshal(data, &node)
+	if err != nil {
+		return fmt.Errorf("decode document: %w", err)
+	}
+	raw, err := yamlToJSON(&node)
+	if err != nil {
+		return fmt.Errorf("decode document: %w", err)
+	}
+	var doc any
+	err = decodeJSON(raw, &doc)
+	if err != nil {
+		return fmt.Errorf("decode document: %w", err)
+	}
+	return validateMetaSchema(doc)
+}
+
+// ValidateMetaSchema checks the encoded document against the official JSON Schema
+// of its OpenAPI version.
+//
+// It complements [OpenAPI.Validate] by checking the document exactly as it's encoded.
+// If the document is invalid, the returned error is [SchemaErrors].
+func (o *OpenAPI) ValidateMetaSchema() error {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	var doc any
+	err = decodeJSON(data, &doc)
+	if err != nil {
+		return err
+	}
+	return validateMetaSchema(doc)
+}
+
+func validateMetaSchema(doc any) error {
+	obj, _ := doc.(map[string]any)
+	version, _ := obj["openapi"].(string)
+	major, rest, _ := strings.Cut(version, ".")
+	minor, _, _ := strings.Cut(rest, ".")
+	load, ok := metaSchemas[major+"."+minor]
+	if !ok {
+		if version == "" {
+			return fmt.Errorf("the document has no openapi version, it's not an OpenAPI 3 document")
+		}
+		return fmt.Errorf("unsupported OpenAPI version %q", version)
+	}
+	meta := load()
+	errs := meta.evaluator.validate(meta.id, meta.root, doc)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
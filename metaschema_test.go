@@ -0,0 +1,157 @@
+package openapi_test
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestValidateDocument(t *testing.T) {
+	for _, path := range []string{"testdata/petstore.json", "testdata/petstore-3.0.yaml"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = openapi.ValidateDocument(data)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+func TestValidateDocument_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want openapi.SchemaError
+	}{
+		{
+			name: "3.1 missing response description",
+			doc: `{
+				"openapi": "3.1.0",
+				"info": {"title": "Pets", "version": "1.0"},
+				"paths": {"/pets": {"get": {"responses": {"200": {}}}}}
+			}`,
+			want: openapi.SchemaError{
+				InstanceLocation: "/paths/~1pets/get/responses/200",
+				KeywordLocation:  "/properties/paths/$ref/patternProperties/^~1/$ref/properties/get/$ref/properties/responses/$ref/patternProperties/^[1-5](?:[0-9]{2}|XX)$/$ref/else/$ref/required",
+				Message:          `missing required property "description"`,
+			},
+		},
+		{
+			name: "3.1 unknown field",
+			doc: `{
+				"openapi": "3.1.0",
+				"info": {"title": "Pets", "version": "1.0", "logo": "logo.png"},
+				"paths": {}
+			}`,
+			want: openapi.SchemaError{
+				InstanceLocation: "/info/logo",
+				KeywordLocation:  "/properties/info/$ref/unevaluatedProperties",
+				Message:          `unevaluated property "logo" is not allowed`,
+			},
+		},
+		{
+			name: "3.1 license identifier and url",
+			doc: `
+openapi: 3.1.0
+info:
+  title: Pets
+  version: "1.0"
+  license:
+    name: MIT
+    identifier: MIT
+    url: https://opensource.org/licenses/MIT
+webhooks: {}
+`,
+			want: openapi.SchemaError{
+				InstanceLocation: "/info/license",
+				KeywordLocation:  "/properties/info/$ref/properties/license/$ref/dependentSchemas/identifier/not",
+				Message:          "the value must not match the schema",
+			},
+		},
+		{
+			name: "3.0 optional path parameter",
+			doc: `{
+				"openapi": "3.0.3",
+				"info": {"title": "Pets", "version": "1.0"},
+				"paths": {"/pets/{id}": {"parameters": [{"name": "id", "in": "path", "schema": {}}]}}
+			}`,
+			want: openapi.SchemaError{
+				InstanceLocation: "/paths/~1pets~1{id}/parameters/0",
+				KeywordLocation:  "/properties/paths/$ref/patternProperties/^\\~1/$ref/properties/parameters/items/oneOf/0/$ref/allOf/2/$ref/oneOf/0/required",
+				Message:          `missing required property "required"`,
+			},
+		},
+		{
+			name: "3.0 missing paths",
+			doc:  `{"openapi": "3.0.3", "info": {"title": "Pets", "version": "1.0"}}`,
+			want: openapi.SchemaError{
+				InstanceLocation: "",
+				KeywordLocation:  "/required",
+				Message:          `missing required property "paths"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := openapi.ValidateDocument([]byte(tt.doc))
+			var errs openapi.SchemaErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected SchemaErrors, got %v", err)
+			}
+			if !slices.Contains(errs, tt.want) {
+				t.Errorf("expected %v, got:\n%v", tt.want, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDocument_UnsupportedVersion(t *testing.T) {
+	docs := []string{
+		`{"swagger": "2.0", "info": {"title": "Pets", "version": "1.0"}, "paths": {}}`,
+		`{"openapi": "4.0.0", "info": {"title": "Pets", "version": "1.0"}, "paths": {}}`,
+	}
+	for _, doc := range docs {
+		err := openapi.ValidateDocument([]byte(doc))
+		var errs openapi.SchemaErrors
+		if err == nil || errors.As(err, &errs) {
+			t.Errorf("expected a version error for %s, got %v", doc, err)
+		}
+	}
+}
+
+func TestOpenAPI_ValidateMetaSchema(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets": openapi.PathItem{
+				Get: openapi.Operation{
+					Responses: openapi.Responses{
+						OK: openapi.Inline(openapi.Response{Description: "The pets"}),
+					},
+				},
+			},
+		}},
+	}
+	err := doc.ValidateMetaSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Components.Links = map[string]openapi.RefOr[openapi.Link]{
+		"Self": openapi.Inline(openapi.Link{Description: "The same pets"}),
+	}
+	err = doc.ValidateMetaSchema()
+	var errs openapi.SchemaErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected SchemaErrors, got %v", err)
+	}
+	if errs[0].InstanceLocation != "/components/links/Self" || errs[0].Message != "the value doesn't match any of the schemas" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNameCollision is returned by [Reflector] when two different types get the same schema name.
+var ErrNameCollision = errors.New("schema name collision")
+
+// DefaultNamer is the default naming strategy for schemas registered by [Reflector].
+//
+// The name is the name of the type without the package. Instantiations of generic types
+// get readable names: Page[User] becomes PageOfUser, Page[*User] becomes PageOfNullableUser,
+// Pair[string, []User] becomes PairOfStringAndListOfUser, and Index[map[string]User]
+// becomes IndexOfMapOfStringToUser.
+func DefaultNamer(t reflect.Type) string {
+	return sanitizeName(readableName(t.Name()))
+}
+
+// name picks the schema name for the type that is not registered yet.
+func (r *Reflector) name(t reflect.Type) (string, error) {
+	namer := r.Namer
+	if namer == nil {
+		namer = DefaultNamer
+	}
+	name := namer(t)
+	if name == "" {
+		return "", fmt.Errorf("empty schema name for %s", t)
+	}
+	if _, taken := r.Schemas[name]; !taken {
+		return name, nil
+	}
+	qualified := sanitizeName(pascalCase(pkgName(t.PkgPath())) + name)
+	if _, taken := r.Schemas[qualified]; !taken {
+		return qualified, nil
+	}
+	return "", fmt.Errorf("%w: %s: both %q and %q are already taken", ErrNameCollision, t, name, qualified)
+}
+
+// readableName converts the type name as reported by reflect into a readable identifier.
+func readableName(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case strings.HasPrefix(s, "*"):
+		// Pointers are reflected as nullable schemas, so they need a name of their own.
+		return "Nullable" + readableName(s[1:])
+	case strings.HasPrefix(s, "[]"):
+		return "ListOf" + readableName(s[2:])
+	case strings.HasPrefix(s, "["):
+		_, elem, _ := strings.Cut(s, "]")
+		return "ListOf" + readableName(elem)
+	case strings.HasPrefix(s, "map["):
+		key, val := splitBracket(s[len("map"):])
+		return "MapOf" + readableName(key) + "To" + readableName(val)
+	}
+	base, args := s, ""
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		base = s[:i]
+		args, _ = splitBracket(s[i:])
+	}
+	// Strip the package path.
+	if i := strings.LastIndexByte(base, '/'); i >= 0 {
+		base = base[i+1:]
+	}
+	if i := strings.LastIndexByte(base, '.'); i >= 0 {
+		base = base[i+1:]
+	}
+	name := exportName(base)
+	if args == "" {
+		return name
+	}
+	var parts []string
+	for _, arg := range splitArgs(args) {
+		parts = append(parts, readableName(arg))
+	}
+	return name + "Of" + strings.Join(parts, "And")
+}
+
+// splitBracket splits the string starting with "[" into the content of the brackets and the rest.
+func splitBracket(s string) (string, string) {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:]
+			}
+		}
+	}
+	return strings.TrimPrefix(s, "["), ""
+}
+
+// splitArgs splits the list of type arguments by top-level commas.
+func splitArgs(s string) []string {
+	var args []string
+	depth := 0
+	start := 0
+	for i, c := range s {
+		switch c {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(args, strings.TrimSpace(s[start:]))
+}
+
+// pkgName returns the last element of the package path, without the major version suffix.
+func pkgName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+// exportName capitalizes the first letter of the name.
+func exportName(name string) string {
+	if name == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// pascalCase converts a package name like "api_models" or "go-models" into "ApiModels" or "GoModels".
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(exportName(part))
+	}
+	return b.String()
+}
+
+// sanitizeName removes from the name the characters not allowed in component names.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_') {
+			return r
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,96 @@
+package openapi_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type User struct {
+	Name string `json:"name"`
+}
+
+func TestDefaultNamer(t *testing.T) {
+	cases := []struct {
+		typ  reflect.Type
+		want string
+	}{
+		{reflect.TypeFor[User](), "User"},
+		{reflect.TypeFor[Page[User]](), "PageOfUser"},
+		{reflect.TypeFor[Page[*User]](), "PageOfNullableUser"},
+		{reflect.TypeFor[Page[[]User]](), "PageOfListOfUser"},
+		{reflect.TypeFor[Pair[string, map[string]User]](), "PairOfStringAndMapOfStringToUser"},
+		{reflect.TypeFor[Page[Page[int]]](), "PageOfPageOfInt"},
+	}
+	for _, c := range cases {
+		got := openapi.DefaultNamer(c.typ)
+		if got != c.want {
+			t.Errorf("DefaultNamer(%s) = %q, want %q", c.typ, got, c.want)
+		}
+	}
+}
+
+func TestReflector_NameCollision(t *testing.T) {
+	r := openapi.Reflector{Schemas: map[string]*openapi.Schema{
+		"User": {Description: "defined manually"},
+	}}
+	s, err := r.Reflect(reflect.TypeFor[User]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Ref != "#/components/schemas/OpenapiTestUser" {
+		t.Errorf("unexpected ref: %s", s.Ref)
+	}
+	if r.Schemas["User"].Description != "defined manually" {
+		t.Error("existing schema must not be overwritten")
+	}
+
+	// Both the name and the qualified name are taken now.
+	type User struct{}
+	_, err = r.Reflect(reflect.TypeFor[User]())
+	if !errors.Is(err, openapi.ErrNameCollision) {
+		t.Errorf("expected name collision, got %v", err)
+	}
+}
+
+func TestReflector_PointerTypeArgs(t *testing.T) {
+	r := openapi.Reflector{}
+	_, err := r.Reflect(reflect.TypeFor[struct {
+		Users    Page[User]  `json:"users"`
+		Nullable Page[*User] `json:"nullable"`
+	}]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Schemas["PageOfUser"] == nil || r.Schemas["PageOfNullableUser"] == nil {
+		t.Errorf("unexpected schemas: %v", r.Schemas)
+	}
+}
+
+func TestReflector_Namer(t *testing.T) {
+	r := openapi.Reflector{Namer: func(t reflect.Type) string {
+		return strings.ToLower(openapi.DefaultNamer(t))
+	}}
+	s, err := r.Reflect(reflect.TypeFor[Page[User]]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Ref != "#/components/schemas/pageofuser" {
+		t.Errorf("unexpected ref: %s", s.Ref)
+	}
+	if r.Schemas["pageofuser"].Properties["items"].Items.Ref != "#/components/schemas/user" {
+		t.Errorf("unexpected schemas: %v", r.Schemas)
+	}
+}
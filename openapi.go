@@ -21,7 +21,10 @@ type OpenAPI struct {
 	// A list of tags used by the OpenAPI Description with additional metadata. The order of the tags can be used to reflect on their order by the parsing tools. Not all tags that are used by the Operation Object must be declared. The tags that are not declared MAY be organized randomly or based on the tools' logic. Each tag name in the list MUST be unique.
 	Tags []Tag `json:"tags,omitzero"`
 	// Additional external documentation.
-	ExternalDocs []ExternalDoc `json:"externalDocs,omitzero"`
+	ExternalDocs ExternalDoc `json:"externalDocs,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 type Info struct {
@@ -39,6 +42,9 @@ type Info struct {
 	License License `json:"license,omitzero"`
 	// REQUIRED. The version of the OpenAPI Document (which is distinct from the OpenAPI Specification version or the version of the API being described or the version of the OpenAPI Description).
 	Version string `json:"version,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 type Contact struct {
@@ -48,6 +54,9 @@ type Contact struct {
 	URL string `json:"url,omitzero"`
 	// The email address of the contact person/organization. This MUST be in the form of an email address.
 	Email string `json:"email,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 type License struct {
@@ -57,6 +66,9 @@ type License struct {
 	Identifier string `json:"identifier,omitzero"`
 	// A URI for the license used for the API. This MUST be in the form of a URI. The url field is mutually exclusive of the identifier field.
 	URL string `json:"url,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // An object representing a Server.
@@ -67,6 +79,9 @@ type Server struct {
 	Description string `json:"description,omitzero"`
 	// A map between a variable name and its value. The value is used for substitution in the server's URL template.
 	Variables map[string]ServerVariable `json:"variables,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // An object representing a Server Variable for server URL template substitution.
@@ -77,24 +92,36 @@ type ServerVariable struct {
 	Default string `json:"default"`
 	// An optional description for the server variable. CommonMark syntax MAY be used for rich text representation.
 	Description string `json:"description,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Holds a set of reusable objects for different aspects of the OAS. All objects defined within the Components Object will have no effect on the API unless they are explicitly referenced from outside the Components Object.
 type Components struct {
-	Schemas         map[string]Schema         `json:"schemas,omitzero"`
-	Responses       map[string]Response       `json:"responses,omitzero"`
-	Parameters      map[string]Parameter      `json:"parameters,omitzero"`
-	Examples        map[string]Example        `json:"examples,omitzero"`
-	RequestBodies   map[string]RequestBody    `json:"requestBodies,omitzero"`
-	Headers         map[string]Header         `json:"headers,omitzero"`
-	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitzero"`
-	Links           map[string]Link           `json:"links,omitzero"`
-	Callbacks       map[string]Callback       `json:"callbacks,omitzero"`
-	PathItems       map[string]PathItem       `json:"pathItems,omitzero"`
+	Schemas         map[string]*Schema               `json:"schemas,omitzero"`
+	Responses       map[string]RefOr[Response]       `json:"responses,omitzero"`
+	Parameters      map[string]RefOr[Parameter]      `json:"parameters,omitzero"`
+	Examples        map[string]RefOr[Example]        `json:"examples,omitzero"`
+	RequestBodies   map[string]RefOr[RequestBody]    `json:"requestBodies,omitzero"`
+	Headers         map[string]RefOr[Header]         `json:"headers,omitzero"`
+	SecuritySchemes map[string]RefOr[SecurityScheme] `json:"securitySchemes,omitzero"`
+	Links           map[string]RefOr[Link]           `json:"links,omitzero"`
+	Callbacks       map[string]RefOr[Callback]       `json:"callbacks,omitzero"`
+	PathItems       map[string]PathItem              `json:"pathItems,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Holds the relative paths to the individual endpoints and their operations. The path is appended to the URL from the Server Object in order to construct the full URL. The Paths Object MAY be empty, due to Access Control List (ACL) constraints.
-type Paths map[string]PathItem
+type Paths struct {
+	// The path items by their relative paths, like "/pets/{id}". A path MUST begin with a forward slash (/). The path is appended (no relative URL resolution) to the expanded URL from the Server Object's url field in order to construct the full URL. Path templating is allowed. When matching URLs, concrete (non-templated) paths would be matched before their templated counterparts.
+	Items map[string]PathItem `json:"-"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
+}
 
 // Describes the operations available on a single path. A Path Item MAY be empty, due to ACL constraints. The path itself is still exposed to the documentation viewer but they will not know which operations and parameters are available.
 type PathItem struct {
@@ -115,7 +142,10 @@ type PathItem struct {
 	// An alternative servers array to service all operations in this path. If a servers array is specified at the OpenAPI Object level, it will be overridden by this value.
 	Servers []Server `json:"servers,omitzero"`
 	// A list of parameters that are applicable for all the operations described under this path. These parameters can be overridden at the operation level, but cannot be removed there. The list MUST NOT include duplicated parameters. A unique parameter is defined by a combination of a name and location. The list can use the Reference Object to link to parameters that are defined in the OpenAPI Object's components.parameters.
-	Parameters []Parameter `json:"parameters,omitzero"`
+	Parameters []RefOr[Parameter] `json:"parameters,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Describes a single API operation on a path.
@@ -131,19 +161,22 @@ type Operation struct {
 	// Unique string used to identify the operation. The id MUST be unique among all operations described in the API. The operationId value is case-sensitive. Tools and libraries MAY use the operationId to uniquely identify an operation, therefore, it is RECOMMENDED to follow common programming naming conventions.
 	OperationID string `json:"operationId,omitzero"`
 	// A list of parameters that are applicable for this operation. If a parameter is already defined at the Path Item, the new definition will override it but can never remove it. The list MUST NOT include duplicated parameters. A unique parameter is defined by a combination of a name and location. The list can use the Reference Object to link to parameters that are defined in the OpenAPI Object's components.parameters.
-	Parameters []Parameter `json:"parameters,omitzero"`
+	Parameters []RefOr[Parameter] `json:"parameters,omitzero"`
 	// The request body applicable for this operation. The requestBody is fully supported in HTTP methods where the HTTP 1.1 specification RFC7231 has explicitly defined semantics for request bodies. In other cases where the HTTP spec is vague (such as GET, HEAD and DELETE), requestBody is permitted but does not have well-defined semantics and SHOULD be avoided if possible.
-	RequestBody RequestBody `json:"requestBody,omitzero"`
+	RequestBody RefOr[RequestBody] `json:"requestBody,omitzero"`
 	// The list of possible responses as they are returned from executing this operation.
 	Responses Responses `json:"responses,omitzero"`
 	// A map of possible out-of band callbacks related to the parent operation. The key is a unique identifier for the Callback Object. Each value in the map is a Callback Object that describes a request that may be initiated by the API provider and the expected responses.
-	Callbacks map[string]Callback `json:"callbacks,omitzero"`
+	Callbacks map[string]RefOr[Callback] `json:"callbacks,omitzero"`
 	// Declares this operation to be deprecated. Consumers SHOULD refrain from usage of the declared operation. Default value is false.
 	Deprecated bool `json:"deprecated,omitzero"`
 	// A declaration of which security mechanisms can be used for this operation. The list of values includes alternative Security Requirement Objects that can be used. Only one of the Security Requirement Objects need to be satisfied to authorize a request. To make security optional, an empty security requirement ({}) can be included in the array. This definition overrides any declared top-level security. To remove a top-level security declaration, an empty array can be used.
 	Security []SecurityRequirement `json:"security,omitzero"`
 	// An alternative servers array to service this operation. If a servers array is specified at the Path Item Object or OpenAPI Object level, it will be overridden by this value.
 	Servers []Server `json:"servers,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Allows referencing an external resource for extended documentation.
@@ -152,6 +185,9 @@ type ExternalDoc struct {
 	URL string `json:"url"`
 	// A description of the target documentation. CommonMark syntax MAY be used for rich text representation.
 	Description string `json:"description,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Describes a single operation parameter.
@@ -159,31 +195,40 @@ type Parameter struct {
 	// REQUIRED. The name of the parameter. Parameter names are case sensitive.
 	Name string `json:"name"`
 	// REQUIRED. The location of the parameter. Possible values are "query", "header", "path" or "cookie".
-	In string `json:"in"`
+	In Location `json:"in"`
 	// A brief description of the parameter. This could contain examples of use. CommonMark syntax MAY be used for rich text representation.
 	Description string `json:"description,omitzero"`
 	// Determines whether this parameter is mandatory. If the parameter location is "path", this field is REQUIRED and its value MUST be true. Otherwise, the field MAY be included and its default value is false.
-	Required bool `json:"required,omitzero"`
+	//
+	// Nil means the field is omitted. Use [Parameter.RequiredOrDefault] for the effective value.
+	Required *bool `json:"required,omitzero"`
 	// Specifies that a parameter is deprecated and SHOULD be transitioned out of usage. Default value is false.
 	Deprecated bool `json:"deprecated,omitzero"`
 	// If true, clients MAY pass a zero-length string value in place of parameters that would otherwise be omitted entirely, which the server SHOULD interpret as the parameter being unused. Default value is false. If style is used, and if behavior is n/a (cannot be serialized), the value of allowEmptyValue SHALL be ignored. Interactions between this field and the parameter's Schema Object are implementation-defined. This field is valid only for query parameters. Use of this field is NOT RECOMMENDED, and it is likely to be removed in a later revision.
-	AllowEmptyValue bool `json:"allowEmptyValue,omitzero"`
+	//
+	// Nil means the field is omitted. Use [Parameter.AllowEmptyValueOrDefault] for the effective value.
+	AllowEmptyValue *bool `json:"allowEmptyValue,omitzero"`
 
 	// Describes how the parameter value will be serialized depending on the type of the parameter value. Default values (based on value of in): for "query" - "form"; for "path" - "simple"; for "header" - "simple"; for "cookie" - "form".
-	Style string `json:"style,omitzero"`
+	Style Style `json:"style,omitzero"`
 	// When this is true, parameter values of type array or object generate separate parameters for each value of the array or key-value pair of the map. For other types of parameters this field has no effect. When style is "form", the default value is true. For all other styles, the default value is false. Note that despite false being the default for deepObject, the combination of false with deepObject is undefined.
-	Explode bool `json:"explode,omitzero"`
+	//
+	// Nil means the field is omitted. Use [Parameter.ExplodeOrDefault] for the effective value.
+	Explode *bool `json:"explode,omitzero"`
 	// When this is true, parameter values are serialized using reserved expansion, as defined by RFC6570, which allows RFC3986's reserved character set, as well as percent-encoded triples, to pass through unchanged, while still percent-encoding all other disallowed characters (including % outside of percent-encoded triples). Applications are still responsible for percent-encoding reserved characters that are not allowed in the query string ([, ], #), or have a special meaning in application/x-www-form-urlencoded (-, &, +); see Appendices C and E for details. This field only applies to parameters with an in value of query. The default value is false.
 	AllowReserved bool `json:"allowReserved,omitzero"`
 	// The schema defining the type used for the parameter.
-	Schema Schema `json:"schema,omitzero"`
+	Schema *Schema `json:"schema,omitzero"`
 	// Example of the parameter's potential value; see Working With Examples.
 	Example any `json:"example,omitzero"`
 	// Examples of the parameter's potential value; see Working With Examples.
-	Examples map[string]Example `json:"examples,omitzero"`
+	Examples map[string]RefOr[Example] `json:"examples,omitzero"`
 
 	// A map containing the representations for the parameter. The key is the media type and the value describes it. The map MUST only contain one entry.
 	Content map[string]MediaType `json:"content,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Describes a single request body.
@@ -194,18 +239,24 @@ type RequestBody struct {
 	Content map[string]MediaType `json:"content"`
 	// Determines if the request body is required in the request. Defaults to false.
 	Required bool `json:"required,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Each Media Type Object provides schema and examples for the media type identified by its key.
 type MediaType struct {
 	// The schema defining the content of the request, response, parameter, or header.
-	Schema Schema `json:"schema,omitzero"`
+	Schema *Schema `json:"schema,omitzero"`
 	// Example of the media type.
 	Example any `json:"example,omitzero"`
 	// Examples of the media type.
-	Examples map[string]Example `json:"examples,omitzero"`
+	Examples map[string]RefOr[Example] `json:"examples,omitzero"`
 	// A map between a property name and its encoding information. The key, being the property name, MUST exist in the schema as a property. The encoding field SHALL only apply to Request Body Objects, and only when the media type is multipart or application/x-www-form-urlencoded. If no Encoding Object is provided for a property, the behavior is determined by the default values documented for the Encoding Object.
 	Encoding map[string]Encoding `json:"encoding,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // A single encoding definition applied to a single schema property.
@@ -213,81 +264,91 @@ type Encoding struct {
 	// The Content-Type for encoding a specific property. The value is a comma-separated list, each element of which is either a specific media type (e.g. image/png) or a wildcard media type (e.g. image/*). Default value depends on the property type as shown in the table below.
 	ContentType string `json:"contentType,omitzero"`
 	// A map allowing additional information to be provided as headers. Content-Type is described separately and SHALL be ignored in this section. This field SHALL be ignored if the request body media type is not a multipart.
-	Headers map[string]Header `json:"headers,omitzero"`
+	Headers map[string]RefOr[Header] `json:"headers,omitzero"`
 	// Describes how a specific property value will be serialized depending on its type. See Parameter Object for details on the style field. The behavior follows the same values as query parameters, including default values. Note that the initial ? used in query strings is not used in application/x-www-form-urlencoded message bodies, and MUST be removed (if using an RFC6570 implementation) or simply not added (if constructing the string manually). This field SHALL be ignored if the request body media type is not application/x-www-form-urlencoded or multipart/form-data. If a value is explicitly defined, then the value of contentType (implicit or explicit) SHALL be ignored.
-	Style string `json:"style,omitzero"`
+	Style Style `json:"style,omitzero"`
 	// When this is true, property values of type array or object generate separate parameters for each value of the array, or key-value-pair of the map. For other types of properties this field has no effect. When style is "form", the default value is true. For all other styles, the default value is false. Note that despite false being the default for deepObject, the combination of false with deepObject is undefined. This field SHALL be ignored if the request body media type is not application/x-www-form-urlencoded or multipart/form-data. If a value is explicitly defined, then the value of contentType (implicit or explicit) SHALL be ignored.
-	Explode bool `json:"explode,omitzero"`
+	//
+	// Nil means the field is omitted. Use [Encoding.ExplodeOrDefault] for the effective value.
+	Explode *bool `json:"explode,omitzero"`
 	// When this is true, parameter values are serialized using reserved expansion, as defined by RFC6570, which allows RFC3986's reserved character set, as well as percent-encoded triples, to pass through unchanged, while still percent-encoding all other disallowed characters (including % outside of percent-encoded triples). Applications are still responsible for percent-encoding reserved characters that are not allowed in the query string ([, ], #), or have a special meaning in application/x-www-form-urlencoded (-, &, +); see Appendices C and E for details. The default value is false. This field SHALL be ignored if the request body media type is not application/x-www-form-urlencoded or multipart/form-data. If a value is explicitly defined, then the value of contentType (implicit or explicit) SHALL be ignored.
 	AllowReserved bool `json:"allowReserved,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // A container for the expected responses of an operation. The container maps a HTTP response code to the expected response.
 type Responses struct {
 	// The documentation of responses other than the ones declared for specific HTTP response codes. Use this field to cover undeclared responses.
-	Default                       Response `json:"default,omitzero"`
-	Continue                      Response `json:"100,omitzero"`
-	SwitchingProtocols            Response `json:"101,omitzero"`
-	Processing                    Response `json:"102,omitzero"`
-	EarlyHints                    Response `json:"103,omitzero"`
-	OK                            Response `json:"200,omitzero"`
-	Created                       Response `json:"201,omitzero"`
-	Accepted                      Response `json:"202,omitzero"`
-	NonAuthoritativeInfo          Response `json:"203,omitzero"`
-	NoContent                     Response `json:"204,omitzero"`
-	ResetContent                  Response `json:"205,omitzero"`
-	PartialContent                Response `json:"206,omitzero"`
-	MultiStatus                   Response `json:"207,omitzero"`
-	AlreadyReported               Response `json:"208,omitzero"`
-	IMUsed                        Response `json:"226,omitzero"`
-	MultipleChoices               Response `json:"300,omitzero"`
-	MovedPermanently              Response `json:"301,omitzero"`
-	Found                         Response `json:"302,omitzero"`
-	SeeOther                      Response `json:"303,omitzero"`
-	NotModified                   Response `json:"304,omitzero"`
-	UseProxy                      Response `json:"305,omitzero"`
-	TemporaryRedirect             Response `json:"307,omitzero"`
-	PermanentRedirect             Response `json:"308,omitzero"`
-	BadRequest                    Response `json:"400,omitzero"`
-	Unauthorized                  Response `json:"401,omitzero"`
-	PaymentRequired               Response `json:"402,omitzero"`
-	Forbidden                     Response `json:"403,omitzero"`
-	NotFound                      Response `json:"404,omitzero"`
-	MethodNotAllowed              Response `json:"405,omitzero"`
-	NotAcceptable                 Response `json:"406,omitzero"`
-	ProxyAuthRequired             Response `json:"407,omitzero"`
-	RequestTimeout                Response `json:"408,omitzero"`
-	Conflict                      Response `json:"409,omitzero"`
-	Gone                          Response `json:"410,omitzero"`
-	LengthRequired                Response `json:"411,omitzero"`
-	PreconditionFailed            Response `json:"412,omitzero"`
-	RequestEntityTooLarge         Response `json:"413,omitzero"`
-	RequestURITooLong             Response `json:"414,omitzero"`
-	UnsupportedMediaType          Response `json:"415,omitzero"`
-	RequestedRangeNotSatisfiable  Response `json:"416,omitzero"`
-	ExpectationFailed             Response `json:"417,omitzero"`
-	Teapot                        Response `json:"418,omitzero"`
-	MisdirectedRequest            Response `json:"421,omitzero"`
-	UnprocessableEntity           Response `json:"422,omitzero"`
-	Locked                        Response `json:"423,omitzero"`
-	FailedDependency              Response `json:"424,omitzero"`
-	TooEarly                      Response `json:"425,omitzero"`
-	UpgradeRequired               Response `json:"426,omitzero"`
-	PreconditionRequired          Response `json:"428,omitzero"`
-	TooManyRequests               Response `json:"429,omitzero"`
-	RequestHeaderFieldsTooLarge   Response `json:"431,omitzero"`
-	UnavailableForLegalReasons    Response `json:"451,omitzero"`
-	InternalServerError           Response `json:"500,omitzero"`
-	NotImplemented                Response `json:"501,omitzero"`
-	BadGateway                    Response `json:"502,omitzero"`
-	ServiceUnavailable            Response `json:"503,omitzero"`
-	GatewayTimeout                Response `json:"504,omitzero"`
-	HTTPVersionNotSupported       Response `json:"505,omitzero"`
-	VariantAlsoNegotiates         Response `json:"506,omitzero"`
-	InsufficientStorage           Response `json:"507,omitzero"`
-	LoopDetected                  Response `json:"508,omitzero"`
-	NotExtended                   Response `json:"510,omitzero"`
-	NetworkAuthenticationRequired Response `json:"511,omitzero"`
+	Default                       RefOr[Response] `json:"default,omitzero"`
+	Continue                      RefOr[Response] `json:"100,omitzero"`
+	SwitchingProtocols            RefOr[Response] `json:"101,omitzero"`
+	Processing                    RefOr[Response] `json:"102,omitzero"`
+	EarlyHints                    RefOr[Response] `json:"103,omitzero"`
+	OK                            RefOr[Response] `json:"200,omitzero"`
+	Created                       RefOr[Response] `json:"201,omitzero"`
+	Accepted                      RefOr[Response] `json:"202,omitzero"`
+	NonAuthoritativeInfo          RefOr[Response] `json:"203,omitzero"`
+	NoContent                     RefOr[Response] `json:"204,omitzero"`
+	ResetContent                  RefOr[Response] `json:"205,omitzero"`
+	PartialContent                RefOr[Response] `json:"206,omitzero"`
+	MultiStatus                   RefOr[Response] `json:"207,omitzero"`
+	AlreadyReported               RefOr[Response] `json:"208,omitzero"`
+	IMUsed                        RefOr[Response] `json:"226,omitzero"`
+	MultipleChoices               RefOr[Response] `json:"300,omitzero"`
+	MovedPermanently              RefOr[Response] `json:"301,omitzero"`
+	Found                         RefOr[Response] `json:"302,omitzero"`
+	SeeOther                      RefOr[Response] `json:"303,omitzero"`
+	NotModified                   RefOr[Response] `json:"304,omitzero"`
+	UseProxy                      RefOr[Response] `json:"305,omitzero"`
+	TemporaryRedirect             RefOr[Response] `json:"307,omitzero"`
+	PermanentRedirect             RefOr[Response] `json:"308,omitzero"`
+	BadRequest                    RefOr[Response] `json:"400,omitzero"`
+	Unauthorized                  RefOr[Response] `json:"401,omitzero"`
+	PaymentRequired               RefOr[Response] `json:"402,omitzero"`
+	Forbidden                     RefOr[Response] `json:"403,omitzero"`
+	NotFound                      RefOr[Response] `json:"404,omitzero"`
+	MethodNotAllowed              RefOr[Response] `json:"405,omitzero"`
+	NotAcceptable                 RefOr[Response] `json:"406,omitzero"`
+	ProxyAuthRequired             RefOr[Response] `json:"407,omitzero"`
+	RequestTimeout                RefOr[Response] `json:"408,omitzero"`
+	Conflict                      RefOr[Response] `json:"409,omitzero"`
+	Gone                          RefOr[Response] `json:"410,omitzero"`
+	LengthRequired                RefOr[Response] `json:"411,omitzero"`
+	PreconditionFailed            RefOr[Response] `json:"412,omitzero"`
+	RequestEntityTooLarge         RefOr[Response] `json:"413,omitzero"`
+	RequestURITooLong             RefOr[Response] `json:"414,omitzero"`
+	UnsupportedMediaType          RefOr[Response] `json:"415,omitzero"`
+	RequestedRangeNotSatisfiable  RefOr[Response] `json:"416,omitzero"`
+	ExpectationFailed             RefOr[Response] `json:"417,omitzero"`
+	Teapot                        RefOr[Response] `json:"418,omitzero"`
+	MisdirectedRequest            RefOr[Response] `json:"421,omitzero"`
+	UnprocessableEntity           RefOr[Response] `json:"422,omitzero"`
+	Locked                        RefOr[Response] `json:"423,omitzero"`
+	FailedDependency              RefOr[Response] `json:"424,omitzero"`
+	TooEarly                      RefOr[Response] `json:"425,omitzero"`
+	UpgradeRequired               RefOr[Response] `json:"426,omitzero"`
+	PreconditionRequired          RefOr[Response] `json:"428,omitzero"`
+	TooManyRequests               RefOr[Response] `json:"429,omitzero"`
+	RequestHeaderFieldsTooLarge   RefOr[Response] `json:"431,omitzero"`
+	UnavailableForLegalReasons    RefOr[Response] `json:"451,omitzero"`
+	InternalServerError           RefOr[Response] `json:"500,omitzero"`
+	NotImplemented                RefOr[Response] `json:"501,omitzero"`
+	BadGateway                    RefOr[Response] `json:"502,omitzero"`
+	ServiceUnavailable            RefOr[Response] `json:"503,omitzero"`
+	GatewayTimeout                RefOr[Response] `json:"504,omitzero"`
+	HTTPVersionNotSupported       RefOr[Response] `json:"505,omitzero"`
+	VariantAlsoNegotiates         RefOr[Response] `json:"506,omitzero"`
+	InsufficientStorage           RefOr[Response] `json:"507,omitzero"`
+	LoopDetected                  RefOr[Response] `json:"508,omitzero"`
+	NotExtended                   RefOr[Response] `json:"510,omitzero"`
+	NetworkAuthenticationRequired RefOr[Response] `json:"511,omitzero"`
+	// Responses for the status codes that don't have a dedicated field above, including the status code ranges like "2XX".
+	Codes map[string]RefOr[Response] `json:"-"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Describes a single response from an API operation, including design-time, static links to operations based on the response.
@@ -295,15 +356,24 @@ type Response struct {
 	// REQUIRED. A description of the response. CommonMark syntax MAY be used for rich text representation.
 	Description string `json:"description"`
 	// Maps a header name to its definition. RFC7230 states header names are case insensitive. If a response header is defined with the name "Content-Type", it SHALL be ignored.
-	Headers map[string]Header `json:"headers,omitzero"`
+	Headers map[string]RefOr[Header] `json:"headers,omitzero"`
 	// A map containing descriptions of potential response payloads. The key is a media type or media type range and the value describes it. For responses that match multiple keys, only the most specific key is applicable. e.g. "text/plain" overrides "text/*"
 	Content map[string]MediaType `json:"content,omitzero"`
 	// A map of operations links that can be followed from the response. The key of the map is a short name for the link, following the naming constraints of the names for Component Objects.
-	Links map[string]Link `json:"links,omitzero"`
+	Links map[string]RefOr[Link] `json:"links,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // A map of possible out-of band callbacks related to the parent operation. Each value in the map is a Path Item Object that describes a set of requests that may be initiated by the API provider and the expected responses. The key value used to identify the Path Item Object is an expression, evaluated at runtime, that identifies a URL to use for the callback operation.
-type Callback map[string]PathItem
+type Callback struct {
+	// The path items by the runtime expressions that identify the URLs of the callback requests, like "{$request.body#/callbackUrl}".
+	Items map[string]PathItem `json:"-"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
+}
 
 // An object grouping an internal or external example value with basic summary and description metadata. This object is typically used in fields named examples (plural), and is a referenceable alternative to older example (singular) fields that do not support referencing or metadata.
 type Example struct {
@@ -315,6 +385,9 @@ type Example struct {
 	Value any `json:"value,omitzero"`
 	// A URI that identifies the literal example. This provides the capability to reference examples that cannot easily be included in JSON or YAML documents. The value field and externalValue field are mutually exclusive. See the rules for resolving Relative References.
 	ExternalValue string `json:"externalValue,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // The Link Object represents a possible design-time link for a response. The presence of a link does not guarantee the caller's ability to successfully invoke it, rather it provides a known relationship and traversal mechanism between responses and other operations.
@@ -331,6 +404,9 @@ type Link struct {
 	Description string `json:"description,omitzero"`
 	// A server object to be used by the target operation.
 	Server Server `json:"server,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Describes a single header for HTTP responses and for individual parts in multipart representations; see the relevant Response Object and Encoding Object documentation for restrictions on which headers can be described.
@@ -338,23 +414,30 @@ type Header struct {
 	// A brief description of the header. This could contain examples of use. CommonMark syntax MAY be used for rich text representation.
 	Description string `json:"description,omitzero"`
 	// Determines whether this header is mandatory. The default value is false.
-	Required bool `json:"required,omitzero"`
+	//
+	// Nil means the field is omitted. Use [Header.RequiredOrDefault] for the effective value.
+	Required *bool `json:"required,omitzero"`
 	// Specifies that the header is deprecated and SHOULD be transitioned out of usage. Default value is false.
 	Deprecated bool `json:"deprecated,omitzero"`
 
 	// Describes how the header value will be serialized. The default (and only legal value for headers) is "simple".
-	Style string `json:"style,omitzero"`
+	Style Style `json:"style,omitzero"`
 	// When this is true, header values of type array or object generate a single header whose value is a comma-separated list of the array items or key-value pairs of the map, see Style Examples. For other data types this field has no effect. The default value is false.
-	Explode bool `json:"explode,omitzero"`
+	//
+	// Nil means the field is omitted. Use [Header.ExplodeOrDefault] for the effective value.
+	Explode *bool `json:"explode,omitzero"`
 	// The schema defining the type used for the header.
-	Schema Schema `json:"schema,omitzero"`
+	Schema *Schema `json:"schema,omitzero"`
 	// Example of the header's potential value; see Working With Examples.
 	Example any `json:"example,omitzero"`
 	// Examples of the header's potential value; see Working With Examples.
-	Examples map[string]Example `json:"examples,omitzero"`
+	Examples map[string]RefOr[Example] `json:"examples,omitzero"`
 
 	// A map containing the representations for the header. The key is the media type and the value describes it. The map MUST only contain one entry.
 	Content map[string]MediaType `json:"content,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Adds metadata to a single tag that is used by the Operation Object. It is not mandatory to have a Tag Object per tag defined in the Operation Object instances.
@@ -365,6 +448,9 @@ type Tag struct {
 	Description string `json:"description,omitzero"`
 	// Additional external documentation for this tag.
 	ExternalDocs ExternalDoc `json:"externalDocs,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // A simple object to allow referencing other components in the OpenAPI Description, internally and externally.
@@ -377,26 +463,30 @@ type Reference struct {
 	Description string `json:"description,omitzero"`
 }
 
-type Schema = any
-
 // Defines a security scheme that can be used by the operations.
+//
+// Only the fields used by the Type are encoded. Use the constructors,
+// like [APIKeyScheme] or [OAuth2Scheme], to set the required ones.
 type SecurityScheme struct {
 	// REQUIRED. The type of the security scheme. Valid values are "apiKey", "http", "mutualTLS", "oauth2", "openIdConnect".
-	Type string `json:"type"`
+	Type SecurityType `json:"type"`
 	// A description for security scheme. CommonMark syntax MAY be used for rich text representation.
 	Description string `json:"description,omitzero"`
-	// REQUIRED. The name of the header, query or cookie parameter to be used.
-	Name string `json:"name"`
-	// REQUIRED. The location of the API key. Valid values are "query", "header", or "cookie".
-	In string `json:"in"`
-	// REQUIRED. The name of the HTTP Authentication scheme to be used in the Authorization header as defined in RFC7235. The values used SHOULD be registered in the IANA Authentication Scheme registry. The value is case-insensitive, as defined in RFC7235.
-	Scheme string `json:"scheme"`
-	// A hint to the client to identify how the bearer token is formatted. Bearer tokens are usually generated by an authorization server, so this information is primarily for documentation purposes.
+	// REQUIRED for "apiKey". The name of the header, query or cookie parameter to be used.
+	Name string `json:"name,omitzero"`
+	// REQUIRED for "apiKey". The location of the API key. Valid values are "query", "header", or "cookie".
+	In Location `json:"in,omitzero"`
+	// REQUIRED for "http". The name of the HTTP Authentication scheme to be used in the Authorization header as defined in RFC7235. The values used SHOULD be registered in the IANA Authentication Scheme registry. The value is case-insensitive, as defined in RFC7235.
+	Scheme string `json:"scheme,omitzero"`
+	// Used by "http" ("bearer"). A hint to the client to identify how the bearer token is formatted. Bearer tokens are usually generated by an authorization server, so this information is primarily for documentation purposes.
 	BearerFormat string `json:"bearerFormat,omitzero"`
-	// REQUIRED. An object containing configuration information for the flow types supported.
+	// REQUIRED for "oauth2". An object containing configuration information for the flow types supported.
 	Flows OAuthFlows `json:"flows,omitzero"`
-	// REQUIRED. Well-known URL to discover the [[OpenID-Connect-Discovery]] provider metadata.
+	// REQUIRED for "openIdConnect". Well-known URL to discover the [[OpenID-Connect-Discovery]] provider metadata.
 	OpenIDConnectURL string `json:"openIdConnectUrl,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Allows configuration of the supported OAuth Flows.
@@ -409,18 +499,27 @@ type OAuthFlows struct {
 	ClientCredentials OAuthFlow `json:"clientCredentials,omitzero"`
 	// Configuration for the OAuth Authorization Code flow. Previously called accessCode in OpenAPI 2.0.
 	AuthorizationCode OAuthFlow `json:"authorizationCode,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Configuration details for a supported OAuth Flow.
+//
+// The fields that the flow doesn't use aren't encoded: the token URL of the implicit flow
+// and the authorization URL of the password and client credentials flows.
 type OAuthFlow struct {
-	// REQUIRED. The authorization URL to be used for this flow. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
-	AuthorizationURL string `json:"authorizationUrl"`
-	// REQUIRED. The token URL to be used for this flow. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
-	TokenURL string `json:"tokenUrl"`
+	// REQUIRED for the implicit and authorization code flows. The authorization URL to be used for this flow. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
+	AuthorizationURL string `json:"authorizationUrl,omitzero"`
+	// REQUIRED for the password, client credentials, and authorization code flows. The token URL to be used for this flow. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
+	TokenURL string `json:"tokenUrl,omitzero"`
 	// The URL to be used for obtaining refresh tokens. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
 	RefreshURL string `json:"refreshUrl,omitzero"`
 	// REQUIRED. The available scopes for the OAuth2 security scheme. A map between the scope name and a short description for it. The map MAY be empty.
 	Scopes map[string]string `json:"scopes"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
 }
 
 // Lists the required security schemes to execute this operation. The name used for each property MUST correspond to a security scheme declared in the Security Schemes under the Components Object.
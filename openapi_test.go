@@ -13,13 +13,13 @@ func Example() {
 		Info: openapi.Info{
 			Title: "Cool service",
 		},
-		Paths: openapi.Paths{
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
 			"/echo": openapi.PathItem{
 				Post: openapi.Operation{
 					Summary: "Scream into the void",
 				},
 			},
-		},
+		}},
 	}
 	jsonDocs, err := json.Marshal(docs)
 	if err != nil {
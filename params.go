@@ -0,0 +1,712 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Encode serializes the value of the parameter by its style and explode,
+// following the style examples of the specification and RFC 6570.
+//
+// The value is encoded into JSON first, so that a struct becomes an object.
+// Object properties are serialized in the order of their names.
+// The result depends on the parameter location:
+//
+//   - "path": the text that replaces the template expression, like ";id=5" for the matrix style.
+//   - "query": the query string part, like "id=3&id=4&id=5" for the exploded form style.
+//   - "header": the header value, like "3,4,5".
+//   - "cookie": the Cookie header part, like "id=3,4,5".
+//
+// Parameters with Content are encoded as a single value of the media type.
+func (p Parameter) Encode(value any) (string, error) {
+	v, err := normalizeJSON(value)
+	if err != nil {
+		return "", err
+	}
+	for mediaType := range p.Content {
+		if !isJSONMediaType(mediaType) {
+			break
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		v = string(data)
+	}
+	pv, err := toParamValue(v)
+	if err != nil {
+		return "", fmt.Errorf("parameter %q: %w", p.Name, err)
+	}
+	result, err := p.encode(pv)
+	if err != nil {
+		return "", fmt.Errorf("parameter %q: %w", p.Name, err)
+	}
+	return result, nil
+}
+
+// A generic JSON value of a parameter, limited to what the styles can represent.
+type paramValue struct {
+	kind paramKind
+	// The value of a primitive.
+	prim string
+	// The items of an array, or the names and values of the object properties interleaved.
+	list []string
+}
+
+type paramKind int
+
+const (
+	paramPrimitive paramKind = iota
+	paramArray
+	paramObject
+)
+
+func toParamValue(v any) (paramValue, error) {
+	switch v := v.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := primitiveString(item)
+			if err != nil {
+				return paramValue{}, err
+			}
+			items[i] = s
+		}
+		return paramValue{kind: paramArray, list: items}, nil
+	case map[string]any:
+		var pairs []string
+		for _, key := range sortedKeys(v) {
+			s, err := primitiveString(v[key])
+			if err != nil {
+				return paramValue{}, err
+			}
+			pairs = append(pairs, key, s)
+		}
+		return paramValue{kind: paramObject, list: pairs}, nil
+	}
+	s, err := primitiveString(v)
+	return paramValue{prim: s}, err
+}
+
+// primitiveString returns the text of the primitive generic JSON value. Null is empty.
+func primitiveString(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", errors.New("nested arrays and objects can't be serialized")
+}
+
+// StyleOrDefault returns the style of the parameter, or the default one for its location:
+// "form" for query and cookie parameters and "simple" for path and header ones.
+func (p Parameter) StyleOrDefault() Style {
+	if p.Style != "" {
+		return p.Style
+	}
+	switch p.In {
+	case InQuery, InCookie:
+		return StyleForm
+	}
+	return StyleSimple
+}
+
+// ExplodeOrDefault reports if array and object values of the parameter are exploded.
+// If Explode is not set, it's true for the form style and false for the others.
+func (p Parameter) ExplodeOrDefault() bool {
+	if p.Explode != nil {
+		return *p.Explode
+	}
+	return p.StyleOrDefault() == StyleForm
+}
+
+// RequiredOrDefault reports if the parameter is mandatory. If Required is not set, it's false.
+func (p Parameter) RequiredOrDefault() bool {
+	return p.Required != nil && *p.Required
+}
+
+// AllowEmptyValueOrDefault reports if the parameter can be empty. If AllowEmptyValue is not set, it's false.
+func (p Parameter) AllowEmptyValueOrDefault() bool {
+	return p.AllowEmptyValue != nil && *p.AllowEmptyValue
+}
+
+// StyleOrDefault returns the style of the property, or "form", the default one for query parameters.
+func (e Encoding) StyleOrDefault() Style {
+	if e.Style != "" {
+		return e.Style
+	}
+	return StyleForm
+}
+
+// ExplodeOrDefault reports if array and object values of the property are exploded.
+// If Explode is not set, it's true for the form style and false for the others.
+func (e Encoding) ExplodeOrDefault() bool {
+	if e.Explode != nil {
+		return *e.Explode
+	}
+	return e.StyleOrDefault() == StyleForm
+}
+
+// ExplodeOrDefault reports if array and object values of the header are exploded. If Explode is not set, it's false.
+func (h Header) ExplodeOrDefault() bool {
+	return h.Explode != nil && *h.Explode
+}
+
+// RequiredOrDefault reports if the header is mandatory. If Required is not set, it's false.
+func (h Header) RequiredOrDefault() bool {
+	return h.Required != nil && *h.Required
+}
+
+// escape percent-encodes all characters except the unreserved ones, as RFC 6570 does.
+// Reserved characters are kept if AllowReserved is set for the query parameter.
+// Header values aren't encoded.
+func (p Parameter) escape(s string) string {
+	if p.In == InHeader {
+		return s
+	}
+	reserved := p.AllowReserved && p.In == InQuery
+	var b strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		if isUnreserved(c) || reserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		if reserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			// Keep the percent-encoded triplets.
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// unescape decodes the percent-encoded text of the parameter.
+func (p Parameter) unescape(s string) (string, error) {
+	switch p.In {
+	case InHeader:
+		return s, nil
+	case InQuery, InCookie:
+		return url.QueryUnescape(s)
+	}
+	return url.PathUnescape(s)
+}
+
+func (p Parameter) encode(v paramValue) (string, error) {
+	name := p.escape(p.Name)
+	list := make([]string, len(v.list))
+	for i, s := range v.list {
+		list[i] = p.escape(s)
+	}
+	explode := p.ExplodeOrDefault()
+	// pairs joins the object properties as "name=value" with the separator.
+	pairs := func(sep string) string {
+		var parts []string
+		for i := 0; i < len(list); i += 2 {
+			parts = append(parts, list[i]+"="+list[i+1])
+		}
+		return strings.Join(parts, sep)
+	}
+	// named joins the "name=item" pairs for each item with the separator.
+	named := func(prefix, sep string) string {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = prefix + name + "=" + item
+		}
+		return strings.Join(parts, sep)
+	}
+	// The separator of the query parameters or cookies.
+	sep := "&"
+	if p.In == InCookie {
+		sep = "; "
+	}
+	switch style := p.StyleOrDefault(); style {
+	case StyleSimple:
+		switch {
+		case v.kind == paramPrimitive:
+			return p.escape(v.prim), nil
+		case v.kind == paramObject && explode:
+			return pairs(","), nil
+		}
+		return strings.Join(list, ","), nil
+	case StyleLabel:
+		switch {
+		case v.kind == paramPrimitive:
+			return "." + p.escape(v.prim), nil
+		case v.kind == paramArray && explode:
+			return "." + strings.Join(list, "."), nil
+		case v.kind == paramObject && explode:
+			return "." + pairs("."), nil
+		}
+		return "." + strings.Join(list, ","), nil
+	case StyleMatrix:
+		switch {
+		case v.kind == paramPrimitive && v.prim == "", len(list) == 0 && v.kind != paramPrimitive:
+			return ";" + name, nil
+		case v.kind == paramPrimitive:
+			return ";" + name + "=" + p.escape(v.prim), nil
+		case v.kind == paramArray && explode:
+			return named(";", ""), nil
+		case v.kind == paramObject && explode:
+			return ";" + pairs(";"), nil
+		}
+		return ";" + name + "=" + strings.Join(list, ","), nil
+	case StyleForm:
+		switch {
+		case v.kind == paramPrimitive:
+			return name + "=" + p.escape(v.prim), nil
+		case v.kind == paramArray && explode:
+			return named("", sep), nil
+		case v.kind == paramObject && explode:
+			return pairs(sep), nil
+		}
+		return name + "=" + strings.Join(list, ","), nil
+	case StyleSpaceDelimited, StylePipeDelimited:
+		if v.kind == paramPrimitive {
+			return "", fmt.Errorf("the %s style doesn't support primitive values", style)
+		}
+		if explode {
+			if v.kind == paramArray {
+				return named("", sep), nil
+			}
+			return pairs(sep), nil
+		}
+		delim := "%20"
+		if style == StylePipeDelimited {
+			delim = "%7C"
+		}
+		return name + "=" + strings.Join(list, delim), nil
+	case StyleDeepObject:
+		if v.kind != paramObject {
+			return "", errors.New("the deepObject style supports only objects")
+		}
+		var parts []string
+		for i := 0; i < len(list); i += 2 {
+			parts = append(parts, name+"%5B"+list[i]+"%5D="+list[i+1])
+		}
+		return strings.Join(parts, sep), nil
+	default:
+		return "", fmt.Errorf("unknown style %q", style)
+	}
+}
+
+// Decode parses the parameter from its serialized form, the reverse of [Parameter.Encode].
+//
+// The raw text is the same as what Encode returns, except that for query parameters
+// it's the whole query string and for cookies it's the whole Cookie header,
+// from which the parameter is picked. The result is a generic JSON value, like the result
+// of [json.Unmarshal] into any, with the types from the parameter schema, so that "10"
+// becomes a number for an integer schema. Values that don't fit the schema are kept as strings.
+// References in the schema are resolved against the document, which may be nil.
+// The second result is false if the parameter isn't present.
+func (p Parameter) Decode(doc *OpenAPI, raw string) (any, bool, error) {
+	v, ok, err := p.decode(doc, raw)
+	if err != nil {
+		return nil, false, fmt.Errorf("parameter %q: %w", p.Name, err)
+	}
+	return v, ok, nil
+}
+
+func (p Parameter) decode(doc *OpenAPI, raw string) (any, bool, error) {
+	if len(p.Content) > 0 {
+		return p.decodeContent(raw)
+	}
+	s := doc.resolveSchema(p.Schema)
+	kind := paramPrimitive
+	switch {
+	case hasSchemaType(s, TypeArray):
+		kind = paramArray
+	case hasSchemaType(s, TypeObject):
+		kind = paramObject
+	}
+	pv, ok, err := p.decodeRaw(raw, kind, s)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	switch pv.kind {
+	case paramArray:
+		var items *Schema
+		if s != nil {
+			items = doc.resolveSchema(s.Items)
+		}
+		result := make([]any, len(pv.list))
+		for i, item := range pv.list {
+			result[i] = coerceValue(items, item)
+		}
+		return result, true, nil
+	case paramObject:
+		result := make(map[string]any, len(pv.list)/2)
+		for i := 0; i < len(pv.list); i += 2 {
+			result[pv.list[i]] = coerceValue(doc.propertySchema(s, pv.list[i]), pv.list[i+1])
+		}
+		return result, true, nil
+	}
+	return coerceValue(s, pv.prim), true, nil
+}
+
+// decodeContent decodes the parameter with Content, which is a single value of the media type.
+func (p Parameter) decodeContent(raw string) (any, bool, error) {
+	pv, ok, err := p.decodeRaw(raw, paramPrimitive, nil)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	for mediaType := range p.Content {
+		if !isJSONMediaType(mediaType) {
+			break
+		}
+		var v any
+		err = decodeJSON([]byte(pv.prim), &v)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid %s value", mediaType)
+		}
+		return v, true, nil
+	}
+	return pv.prim, true, nil
+}
+
+// propertySchema returns the schema of the object property, if there is one.
+func (o *OpenAPI) propertySchema(s *Schema, name string) *Schema {
+	if s == nil {
+		return nil
+	}
+	if prop, ok := s.Properties[name]; ok {
+		return o.resolveSchema(prop)
+	}
+	return o.resolveSchema(s.AdditionalProperties)
+}
+
+// decodeRaw splits the serialized parameter into the unescaped primitive, array items, or object properties.
+func (p Parameter) decodeRaw(raw string, kind paramKind, s *Schema) (paramValue, bool, error) {
+	explode := p.ExplodeOrDefault()
+	switch style := p.StyleOrDefault(); style {
+	case StyleSimple:
+		if p.In == InPath || p.In == InHeader {
+			return p.splitValue(raw, kind, ",", explode)
+		}
+		return paramValue{}, false, fmt.Errorf("the simple style isn't supported in %s", p.In)
+	case StyleLabel:
+		rest, ok := strings.CutPrefix(raw, ".")
+		if !ok {
+			return paramValue{}, true, errors.New(`the label value must start with "."`)
+		}
+		sep := ","
+		if explode && kind != paramPrimitive {
+			sep = "."
+		}
+		return p.splitValue(rest, kind, sep, explode)
+	case StyleMatrix:
+		rest, ok := strings.CutPrefix(raw, ";")
+		if !ok {
+			return paramValue{}, true, errors.New(`the matrix value must start with ";"`)
+		}
+		if explode && kind != paramPrimitive {
+			parts := strings.Split(rest, ";")
+			if kind == paramObject {
+				return p.splitValue(strings.Join(parts, ","), kind, ",", true)
+			}
+			var items []string
+			for _, part := range parts {
+				name, value, _ := strings.Cut(part, "=")
+				if name != p.escape(p.Name) {
+					return paramValue{}, true, fmt.Errorf("unexpected name %q in the matrix value", name)
+				}
+				item, err := p.unescape(value)
+				if err != nil {
+					return paramValue{}, true, err
+				}
+				items = append(items, item)
+			}
+			if rest == "" || rest == p.escape(p.Name) {
+				items = []string{}
+			}
+			return paramValue{kind: kind, list: items}, true, nil
+		}
+		name, value, _ := strings.Cut(rest, "=")
+		if name != p.escape(p.Name) {
+			return paramValue{}, true, fmt.Errorf("unexpected name %q in the matrix value", name)
+		}
+		return p.splitValue(value, kind, ",", false)
+	case StyleForm, StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject:
+		pairs, err := p.queryPairs(raw)
+		if err != nil {
+			return paramValue{}, true, err
+		}
+		return p.decodePairs(pairs, kind, s, style, explode)
+	default:
+		return paramValue{}, true, fmt.Errorf("unknown style %q", style)
+	}
+}
+
+// splitValue splits the value of the simple, label, or matrix style.
+func (p Parameter) splitValue(raw string, kind paramKind, sep string, explode bool) (paramValue, bool, error) {
+	if kind == paramPrimitive {
+		prim, err := p.unescape(raw)
+		return paramValue{prim: prim}, true, err
+	}
+	var parts []string
+	if raw != "" {
+		parts = strings.Split(raw, sep)
+	}
+	var list []string
+	for _, part := range parts {
+		if kind == paramObject && explode {
+			name, value, ok := strings.Cut(part, "=")
+			if !ok {
+				return paramValue{}, true, fmt.Errorf("expected name=value, got %q", part)
+			}
+			list = append(list, name, value)
+			continue
+		}
+		list = append(list, part)
+	}
+	if kind == paramObject && len(list)%2 != 0 {
+		return paramValue{}, true, errors.New("expected pairs of property names and values")
+	}
+	for i, item := range list {
+		var err error
+		list[i], err = p.unescape(item)
+		if err != nil {
+			return paramValue{}, true, err
+		}
+	}
+	if list == nil {
+		list = []string{}
+	}
+	return paramValue{kind: kind, list: list}, true, nil
+}
+
+// A query parameter or a cookie with the escaped name and value.
+type queryPair struct {
+	name  string
+	value string
+}
+
+// queryPairs splits the query string or the Cookie header into unescaped names with escaped values.
+func (p Parameter) queryPairs(raw string) ([]queryPair, error) {
+	sep := "&"
+	if p.In == InCookie {
+		sep = ";"
+	}
+	var pairs []queryPair
+	for _, part := range strings.Split(raw, sep) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(part, "=")
+		name, err := p.unescape(name)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, queryPair{name, value})
+	}
+	return pairs, nil
+}
+
+// decodePairs picks the parameter from the query parameters or cookies.
+func (p Parameter) decodePairs(pairs []queryPair, kind paramKind, s *Schema, style Style, explode bool) (paramValue, bool, error) {
+	if style == StyleDeepObject {
+		var list []string
+		for _, pair := range pairs {
+			rest, ok := strings.CutPrefix(pair.name, p.Name+"[")
+			key, ok2 := strings.CutSuffix(rest, "]")
+			if !ok || !ok2 {
+				continue
+			}
+			value, err := p.unescape(pair.value)
+			if err != nil {
+				return paramValue{}, true, err
+			}
+			list = append(list, key, value)
+		}
+		if list == nil {
+			return paramValue{}, false, nil
+		}
+		if kind != paramObject {
+			return paramValue{}, true, errors.New("the deepObject style supports only objects")
+		}
+		return paramValue{kind: kind, list: list}, true, nil
+	}
+	if explode && kind == paramObject {
+		// Each property is a separate parameter.
+		var list []string
+		for _, pair := range pairs {
+			if s != nil && len(s.Properties) > 0 && s.Properties[pair.name] == nil {
+				continue
+			}
+			value, err := p.unescape(pair.value)
+			if err != nil {
+				return paramValue{}, true, err
+			}
+			list = append(list, pair.name, value)
+		}
+		return paramValue{kind: kind, list: list}, list != nil, nil
+	}
+	var values []string
+	for _, pair := range pairs {
+		if pair.name == p.Name {
+			values = append(values, pair.value)
+		}
+	}
+	if values == nil {
+		return paramValue{}, false, nil
+	}
+	if kind == paramPrimitive {
+		prim, err := p.unescape(values[0])
+		return paramValue{prim: prim}, true, err
+	}
+	if explode && kind == paramArray {
+		for i, value := range values {
+			var err error
+			values[i], err = p.unescape(value)
+			if err != nil {
+				return paramValue{}, true, err
+			}
+		}
+		if len(values) == 1 && values[0] == "" {
+			values = []string{}
+		}
+		return paramValue{kind: kind, list: values}, true, nil
+	}
+	value := values[0]
+	switch style {
+	case StyleSpaceDelimited, StylePipeDelimited:
+		// The delimiter may be escaped or not, so the value is unescaped before splitting.
+		unescaped, err := p.unescape(value)
+		if err != nil {
+			return paramValue{}, true, err
+		}
+		delim := " "
+		if style == StylePipeDelimited {
+			delim = "|"
+		}
+		var list []string
+		if unescaped != "" {
+			list = strings.Split(unescaped, delim)
+		}
+		if kind == paramObject && len(list)%2 != 0 {
+			return paramValue{}, true, errors.New("expected pairs of property names and values")
+		}
+		if list == nil {
+			list = []string{}
+		}
+		return paramValue{kind: kind, list: list}, true, nil
+	}
+	return p.splitValue(value, kind, ",", false)
+}
+
+// coerceValue converts the raw string into the first primitive type of the schema it's valid for.
+func coerceValue(s *Schema, raw string) any {
+	if s == nil {
+		return raw
+	}
+	for _, t := range s.Type {
+		switch t {
+		case TypeInteger, TypeNumber:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+				return jsonNumber(raw)
+			}
+		case TypeBoolean:
+			if raw == "true" || raw == "false" {
+				return raw == "true"
+			}
+		case TypeNull:
+			if raw == "" {
+				return nil
+			}
+		case TypeString:
+			return raw
+		}
+	}
+	return raw
+}
+
+// jsonNumber returns the number in its canonical JSON form,
+// so that "+1" and ".5" are accepted like "1" and "0.5".
+func jsonNumber(raw string) json.Number {
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return json.Number(strconv.FormatInt(n, 10))
+	}
+	f, _ := strconv.ParseFloat(raw, 64)
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// resolveSchema follows the references to the schemas in Components.
+// It returns the last schema it can resolve.
+func (o *OpenAPI) resolveSchema(s *Schema) *Schema {
+	for range maxEvalDepth {
+		if o == nil || s == nil || s.Ref == "" {
+			return s
+		}
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		next := o.Components.Schemas[unescapePointer(name)]
+		if !ok || next == nil {
+			return s
+		}
+		s = next
+	}
+	return s
+}
+
+func hasSchemaType(s *Schema, t string) bool {
+	if s == nil {
+		return false
+	}
+	for _, name := range s.Type {
+		if name == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONMediaType reports if the media type, possibly with parameters, is JSON, like "application/json" or "application/problem+json".
+func isJSONMediaType(mediaType string) bool {
+	mt, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// matchMediaType finds the most specific media type of the content for the Content-Type header value,
+// trying the exact media type, then the "type/*" range, then "*/*".
+func matchMediaType(content map[string]MediaType, contentType string) (string, MediaType, bool) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", MediaType{}, false
+	}
+	major, _, _ := strings.Cut(mt, "/")
+	for _, want := range []string{mt, major + "/*", "*/*"} {
+		for _, key := range sortedKeys(content) {
+			keyType, _, err := mime.ParseMediaType(key)
+			if err == nil && keyType == want {
+				return key, content[key], true
+			}
+		}
+	}
+	return "", MediaType{}, false
+}
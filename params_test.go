@@ -0,0 +1,237 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestParameter_Encode(t *testing.T) {
+	str := &openapi.Schema{Type: openapi.Types{openapi.TypeString}}
+	arr := &openapi.Schema{Type: openapi.Types{openapi.TypeArray}, Items: str}
+	obj := &openapi.Schema{
+		Type: openapi.Types{openapi.TypeObject},
+		Properties: map[string]*openapi.Schema{
+			"R": {Type: openapi.Types{openapi.TypeInteger}},
+			"G": {Type: openapi.Types{openapi.TypeInteger}},
+			"B": {Type: openapi.Types{openapi.TypeInteger}},
+		},
+	}
+	primitive := "blue"
+	array := []string{"blue", "black", "brown"}
+	object := map[string]int{"R": 100, "G": 200, "B": 150}
+
+	// The style examples of the specification. Object properties are sorted by name.
+	tests := []struct {
+		in      openapi.Location
+		style   openapi.Style
+		explode bool
+		// The expected encoding of the primitive, the array, and the object, if supported.
+		primitive, array, object string
+	}{
+		{"path", "matrix", false, ";color=blue", ";color=blue,black,brown", ";color=B,150,G,200,R,100"},
+		{"path", "matrix", true, ";color=blue", ";color=blue;color=black;color=brown", ";B=150;G=200;R=100"},
+		{"path", "label", false, ".blue", ".blue,black,brown", ".B,150,G,200,R,100"},
+		{"path", "label", true, ".blue", ".blue.black.brown", ".B=150.G=200.R=100"},
+		{"path", "simple", false, "blue", "blue,black,brown", "B,150,G,200,R,100"},
+		{"path", "simple", true, "blue", "blue,black,brown", "B=150,G=200,R=100"},
+		{"query", "form", false, "color=blue", "color=blue,black,brown", "color=B,150,G,200,R,100"},
+		{"query", "form", true, "color=blue", "color=blue&color=black&color=brown", "B=150&G=200&R=100"},
+		{"query", "spaceDelimited", false, "", "color=blue%20black%20brown", "color=B%20150%20G%20200%20R%20100"},
+		{"query", "pipeDelimited", false, "", "color=blue%7Cblack%7Cbrown", "color=B%7C150%7CG%7C200%7CR%7C100"},
+		{"query", "deepObject", true, "", "", "color%5BB%5D=150&color%5BG%5D=200&color%5BR%5D=100"},
+		{"header", "", false, "blue", "blue,black,brown", "B,150,G,200,R,100"},
+		{"header", "", true, "blue", "blue,black,brown", "B=150,G=200,R=100"},
+		{"cookie", "", false, "color=blue", "color=blue,black,brown", "color=B,150,G,200,R,100"},
+		{"cookie", "", true, "color=blue", "color=blue; color=black; color=brown", "B=150; G=200; R=100"},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct {
+			schema *openapi.Schema
+			value  any
+			want   string
+		}{
+			{str, primitive, tt.primitive},
+			{arr, array, tt.array},
+			{obj, object, tt.object},
+		} {
+			p := openapi.Parameter{Name: "color", In: tt.in, Style: tt.style, Explode: &tt.explode, Schema: c.schema}
+			got, err := p.Encode(c.value)
+			if c.want == "" {
+				if err == nil {
+					t.Errorf("%s %s explode=%v: expected an error for %v, got %q", tt.in, tt.style, tt.explode, c.value, got)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s %s explode=%v: %v", tt.in, tt.style, tt.explode, err)
+				continue
+			}
+			if got != c.want {
+				t.Errorf("%s %s explode=%v: got %q, want %q", tt.in, tt.style, tt.explode, got, c.want)
+			}
+
+			// Decoding is the reverse of encoding.
+			decoded, ok, err := p.Decode(nil, got)
+			if err != nil || !ok {
+				t.Errorf("%s %s explode=%v: cannot decode %q: %v", tt.in, tt.style, tt.explode, got, err)
+				continue
+			}
+			gotJSON, _ := json.Marshal(decoded)
+			wantJSON, _ := json.Marshal(c.value)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("%s %s explode=%v: decoded %q into %s, want %s", tt.in, tt.style, tt.explode, got, gotJSON, wantJSON)
+			}
+		}
+	}
+}
+
+func TestParameter_Encode_Empty(t *testing.T) {
+	tests := []struct {
+		in    openapi.Location
+		style openapi.Style
+		want  string
+	}{
+		{"path", "matrix", ";color"},
+		{"path", "label", "."},
+		{"path", "simple", ""},
+		{"query", "form", "color="},
+	}
+	for _, tt := range tests {
+		p := openapi.Parameter{Name: "color", In: tt.in, Style: tt.style}
+		got, err := p.Encode("")
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.style, got, err, tt.want)
+		}
+	}
+}
+
+func TestParameter_Encode_Escape(t *testing.T) {
+	p := openapi.Parameter{Name: "q", In: "query"}
+	got, _ := p.Encode("a b/c,d")
+	if got != "q=a%20b%2Fc%2Cd" {
+		t.Errorf("unexpected query: %q", got)
+	}
+	p.AllowReserved = true
+	got, _ = p.Encode("a b/c,d")
+	if got != "q=a%20b/c,d" {
+		t.Errorf("unexpected query with reserved characters: %q", got)
+	}
+	p = openapi.Parameter{Name: "id", In: "path"}
+	got, _ = p.Encode([]any{"a,b", "c"})
+	if got != "a%2Cb,c" {
+		t.Errorf("unexpected path: %q", got)
+	}
+	value, _, _ := p.Decode(nil, got)
+	if s, _ := value.(string); s != "a,b,c" {
+		t.Errorf("unexpected decoded path without a schema: %v", value)
+	}
+}
+
+func TestParameter_Decode(t *testing.T) {
+	doc := &openapi.OpenAPI{
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"IDs": {Type: openapi.Types{openapi.TypeArray}, Items: &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}}},
+			},
+		},
+	}
+	ids := &openapi.Schema{Ref: "#/components/schemas/IDs"}
+	tests := []struct {
+		param openapi.Parameter
+		raw   string
+		want  string
+	}{
+		{openapi.Parameter{Name: "id", In: "query", Schema: ids}, "x=1&id=3&y=2&id=4", `[3,4]`},
+		{openapi.Parameter{Name: "id", In: "query", Schema: ids, Style: "pipeDelimited"}, "id=3|4", `[3,4]`},
+		{openapi.Parameter{Name: "id", In: "query", Schema: ids}, "id=", `[]`},
+		{openapi.Parameter{Name: "id", In: "query", Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeBoolean}}}, "id=true", `true`},
+		{openapi.Parameter{Name: "id", In: "query", Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}}}, "id=ten", `"ten"`},
+		{openapi.Parameter{Name: "f", In: "query", Style: "deepObject", Explode: ptr(true), Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeObject}}}, "f[a]=1&f[b]=x+y", `{"a":"1","b":"x y"}`},
+		{openapi.Parameter{Name: "s", In: "cookie"}, "a=1; s=hello%20world", `"hello world"`},
+		{openapi.Parameter{Name: "id", In: "path", Style: "matrix", Explode: ptr(true), Schema: ids}, ";id=3;id=4", `[3,4]`},
+		{
+			openapi.Parameter{Name: "filter", In: "query", Content: map[string]openapi.MediaType{"application/json": {}}},
+			"filter=%7B%22a%22%3A1%7D",
+			`{"a":1}`,
+		},
+	}
+	for _, tt := range tests {
+		got, ok, err := tt.param.Decode(doc, tt.raw)
+		if err != nil || !ok {
+			t.Errorf("%q: unexpected result %v, %v", tt.raw, ok, err)
+			continue
+		}
+		gotJSON, _ := json.Marshal(got)
+		if string(gotJSON) != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.raw, gotJSON, tt.want)
+		}
+	}
+
+	_, ok, err := openapi.Parameter{Name: "id", In: "query"}.Decode(doc, "x=1")
+	if ok || err != nil {
+		t.Errorf("expected a missing parameter, got %v, %v", ok, err)
+	}
+	_, _, err = openapi.Parameter{Name: "id", In: "path", Style: "label"}.Decode(doc, "5")
+	if err == nil {
+		t.Error("expected an error for a label without a dot")
+	}
+}
+
+func TestParameter_OrDefault(t *testing.T) {
+	tests := []struct {
+		param   openapi.Parameter
+		style   openapi.Style
+		explode bool
+	}{
+		{openapi.Parameter{In: "query"}, "form", true},
+		{openapi.Parameter{In: "query", Explode: ptr(false)}, "form", false},
+		{openapi.Parameter{In: "query", Style: "deepObject"}, "deepObject", false},
+		{openapi.Parameter{In: "cookie"}, "form", true},
+		{openapi.Parameter{In: "path"}, "simple", false},
+		{openapi.Parameter{In: "path", Style: "label", Explode: ptr(true)}, "label", true},
+		{openapi.Parameter{In: "header"}, "simple", false},
+	}
+	for _, tt := range tests {
+		if got := tt.param.StyleOrDefault(); got != tt.style {
+			t.Errorf("%s: got style %q, want %q", tt.param.In, got, tt.style)
+		}
+		if got := tt.param.ExplodeOrDefault(); got != tt.explode {
+			t.Errorf("%s %s: got explode %v, want %v", tt.param.In, tt.param.Style, got, tt.explode)
+		}
+	}
+
+	if (openapi.Encoding{}).ExplodeOrDefault() != true {
+		t.Error("encoding must be exploded by default")
+	}
+	if (openapi.Encoding{Style: "pipeDelimited"}).ExplodeOrDefault() != false {
+		t.Error("pipeDelimited encoding must not be exploded by default")
+	}
+	if (openapi.Header{}).ExplodeOrDefault() || (openapi.Header{}).RequiredOrDefault() {
+		t.Error("header must not be exploded or required by default")
+	}
+	if (openapi.Parameter{}).RequiredOrDefault() || (openapi.Parameter{}).AllowEmptyValueOrDefault() {
+		t.Error("parameter must not be required or allow empty values by default")
+	}
+}
+
+func TestParameter_ExplicitFalse(t *testing.T) {
+	p := openapi.Parameter{Name: "color", In: "query", Explode: ptr(false), Required: ptr(false)}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"color","in":"query","required":false,"explode":false}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+	var got openapi.Parameter
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Explode == nil || *got.Explode || got.Required == nil || *got.Required || got.AllowEmptyValue != nil {
+		t.Errorf("unexpected round trip: %+v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Parse decodes an OpenAPI document from its JSON form.
+//
+// Encoding the result back produces a document semantically equal to the input,
+// including extensions, references, and unknown schema keywords.
+// Numbers in untyped values, like examples, are kept as [json.Number].
+func Parse(data []byte) (*OpenAPI, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc OpenAPI
+	err := dec.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("decode document: unexpected data after the document")
+	}
+	if doc.Version == "" {
+		return nil, errors.New("the document has no openapi version, it's not an OpenAPI 3 document")
+	}
+	if !strings.HasPrefix(doc.Version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", doc.Version)
+	}
+	return &doc, nil
+}
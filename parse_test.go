@@ -0,0 +1,51 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	input, err := os.ReadFile("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Paths.Items["/pets"].Post.Responses.Codes["599"].Value.Description == "" {
+		t.Error("unknown status code is lost")
+	}
+	if doc.Paths.Extensions["x-internal"] == nil {
+		t.Error("paths extension is lost")
+	}
+	if doc.Paths.Items["/pets"].Post.Callbacks["created"].Value.Extensions["x-retry"] == nil {
+		t.Error("callback extension is lost")
+	}
+	output, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, output, input) {
+		t.Errorf("round trip changed the document: %s", output)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	inputs := []string{
+		`{"swagger": "2.0", "info": {"title": "old", "version": "1"}}`,
+		`{"openapi": "4.0.0", "info": {"title": "future", "version": "1"}}`,
+		`{"openapi": "3.1.0"} {}`,
+		`{"openapi": "3.1.0", "paths": []}`,
+	}
+	for _, input := range inputs {
+		_, err := openapi.Parse([]byte(input))
+		if err == nil {
+			t.Errorf("expected an error for %s", input)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Problem details of an HTTP API error, as defined by RFC 9457.
+type Problem struct {
+	// A URI reference that identifies the problem type. If empty, it's "about:blank".
+	Type string `json:"type,omitzero"`
+	// A short, human-readable summary of the problem type.
+	Title string `json:"title,omitzero"`
+	// The HTTP status code.
+	Status int `json:"status,omitzero"`
+	// A human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitzero"`
+	// A URI reference that identifies the specific occurrence of the problem.
+	Instance string `json:"instance,omitzero"`
+	// The individual violations that caused the problem. It's an extension member.
+	Errors []FieldError `json:"errors,omitzero"`
+}
+
+// A violation in a specific part of an HTTP request or response.
+type FieldError struct {
+	// JSON Pointer to the invalid part of the message. It starts with "/body",
+	// followed by the location in the body, or with the parameter location and name,
+	// like "/query/limit" or "/header/X-Request-ID".
+	Pointer string `json:"pointer"`
+	// The description of the violation.
+	Detail string `json:"detail"`
+}
+
+func (e FieldError) Error() string {
+	return e.Pointer + ": " + e.Detail
+}
+
+// All the violations found in an HTTP request or response.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// writeProblem writes the problem as an "application/problem+json" response.
+// The title defaults to the status text.
+func writeProblem(w http.ResponseWriter, p Problem) {
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
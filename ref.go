@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Either a [Reference] to a component or the component itself.
+//
+// If Ref is not empty, the object is encoded as a Reference Object, and Value is ignored.
+// Otherwise, Value is encoded inline.
+type RefOr[T any] struct {
+	Reference
+	// The object itself, used if Ref is empty.
+	Value T
+}
+
+// Ref creates a reference to a component, like "#/components/parameters/PageSize".
+func Ref[T any](ref string) RefOr[T] {
+	return RefOr[T]{Reference: Reference{Ref: ref}}
+}
+
+// Inline wraps the object to be used where a reference is also allowed.
+func Inline[T any](value T) RefOr[T] {
+	return RefOr[T]{Value: value}
+}
+
+// IsRef reports if the object is a reference.
+func (r RefOr[T]) IsRef() bool {
+	return r.Ref != ""
+}
+
+// IsZero reports if the object is neither a reference nor has a non-zero value.
+func (r RefOr[T]) IsZero() bool {
+	return r.Reference == Reference{} && reflect.ValueOf(&r.Value).Elem().IsZero()
+}
+
+func (r RefOr[T]) MarshalJSON() ([]byte, error) {
+	if r.IsRef() {
+		return json.Marshal(r.Reference)
+	}
+	return json.Marshal(r.Value)
+}
+
+func (r *RefOr[T]) UnmarshalJSON(data []byte) error {
+	*r = RefOr[T]{}
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	if _, isRef := fields["$ref"]; isRef {
+		return decodeJSON(data, &r.Reference)
+	}
+	return decodeJSON(data, &r.Value)
+}
+
+// Resolve returns the object, following the reference if needed.
+//
+// Only local references to components of the same type are supported,
+// like "#/components/parameters/PageSize" for [Parameter].
+// The reference can point to another reference.
+func (r RefOr[T]) Resolve(doc *OpenAPI) (T, error) {
+	seen := make(map[string]bool)
+	for r.IsRef() {
+		if seen[r.Ref] {
+			var zero T
+			return zero, fmt.Errorf("circular reference %q", r.Ref)
+		}
+		seen[r.Ref] = true
+		next, err := lookupRef[T](doc, r.Ref)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		r = next
+	}
+	return r.Value, nil
+}
+
+// lookupRef finds the component that the local reference points to.
+func lookupRef[T any](doc *OpenAPI, ref string) (RefOr[T], error) {
+	kind, name, err := parseComponentRef(ref)
+	if err != nil {
+		return RefOr[T]{}, err
+	}
+	c := doc.Components
+	var found any
+	var ok bool
+	var zero T
+	switch any(zero).(type) {
+	case Response:
+		found, ok = lookupComponent(kind, "responses", c.Responses, name)
+	case Parameter:
+		found, ok = lookupComponent(kind, "parameters", c.Parameters, name)
+	case Example:
+		found, ok = lookupComponent(kind, "examples", c.Examples, name)
+	case RequestBody:
+		found, ok = lookupComponent(kind, "requestBodies", c.RequestBodies, name)
+	case Header:
+		found, ok = lookupComponent(kind, "headers", c.Headers, name)
+	case SecurityScheme:
+		found, ok = lookupComponent(kind, "securitySchemes", c.SecuritySchemes, name)
+	case Link:
+		found, ok = lookupComponent(kind, "links", c.Links, name)
+	case Callback:
+		found, ok = lookupComponent(kind, "callbacks", c.Callbacks, name)
+	default:
+		return RefOr[T]{}, fmt.Errorf("cannot resolve reference to %T", zero)
+	}
+	if !ok {
+		return RefOr[T]{}, fmt.Errorf("unresolved reference %q", ref)
+	}
+	return found.(RefOr[T]), nil
+}
+
+func lookupComponent[T any](kind, wantKind string, components map[string]RefOr[T], name string) (any, bool) {
+	if kind != wantKind {
+		return nil, false
+	}
+	c, ok := components[name]
+	return c, ok
+}
+
+// parseComponentRef splits a reference like "#/components/parameters/PageSize"
+// into the component kind and name.
+func parseComponentRef(ref string) (string, string, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/components/")
+	if !ok {
+		return "", "", fmt.Errorf("unsupported reference %q: only local references to components are supported", ref)
+	}
+	kind, name, ok := strings.Cut(pointer, "/")
+	if !ok || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid component reference %q", ref)
+	}
+	name, err := url.PathUnescape(name)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid component reference %q: %w", ref, err)
+	}
+	return kind, unescapePointer(name), nil
+}
+
+// unescapePointer decodes a JSON Pointer reference token.
+func unescapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~1", "/")
+	return strings.ReplaceAll(token, "~0", "~")
+}
+
+// escapePointer encodes a string as a JSON Pointer reference token.
+func escapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
@@ -0,0 +1,83 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestRefOr_MarshalJSON(t *testing.T) {
+	op := openapi.Operation{
+		Parameters: []openapi.RefOr[openapi.Parameter]{
+			openapi.Ref[openapi.Parameter]("#/components/parameters/PageSize"),
+			openapi.Inline(openapi.Parameter{Name: "q", In: "query"}),
+		},
+		Responses: openapi.Responses{
+			OK: openapi.RefOr[openapi.Response]{Reference: openapi.Reference{
+				Ref:         "#/components/responses/Users",
+				Description: "The list of users",
+			}},
+		},
+	}
+	got, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+		"parameters": [
+			{"$ref": "#/components/parameters/PageSize"},
+			{"name": "q", "in": "query"}
+		],
+		"responses": {
+			"200": {"$ref": "#/components/responses/Users", "description": "The list of users"}
+		}
+	}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected operation: %s", got)
+	}
+
+	var parsed openapi.Operation
+	err = json.Unmarshal(got, &parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Parameters[0].IsRef() || parsed.Parameters[1].IsRef() {
+		t.Errorf("unexpected parameters: %+v", parsed.Parameters)
+	}
+	if parsed.Responses.OK.Description != "The list of users" {
+		t.Errorf("unexpected response: %+v", parsed.Responses.OK)
+	}
+}
+
+func TestRefOr_Resolve(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Components: openapi.Components{
+			Parameters: map[string]openapi.RefOr[openapi.Parameter]{
+				"PageSize": openapi.Inline(openapi.Parameter{Name: "page_size", In: "query"}),
+				"Limit":    openapi.Ref[openapi.Parameter]("#/components/parameters/PageSize"),
+				"Loop":     openapi.Ref[openapi.Parameter]("#/components/parameters/Loop"),
+			},
+		},
+	}
+	p, err := openapi.Ref[openapi.Parameter]("#/components/parameters/Limit").Resolve(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "page_size" {
+		t.Errorf("unexpected parameter: %+v", p)
+	}
+
+	bad := []openapi.RefOr[openapi.Parameter]{
+		openapi.Ref[openapi.Parameter]("#/components/parameters/Missing"),
+		openapi.Ref[openapi.Parameter]("#/components/headers/PageSize"),
+		openapi.Ref[openapi.Parameter]("#/components/parameters/Loop"),
+		openapi.Ref[openapi.Parameter]("common.yaml#/components/parameters/PageSize"),
+	}
+	for _, ref := range bad {
+		_, err := ref.Resolve(&doc)
+		if err == nil {
+			t.Errorf("expected an error for %s", ref.Ref)
+		}
+	}
+}
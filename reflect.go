@@ -0,0 +1,438 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// The default prefix of $ref pointing to a schema registered by [Reflector].
+const DefaultRefPrefix = "#/components/schemas/"
+
+// Reflector generates schemas from Go types.
+//
+// The schemas describe the JSON produced for the type by encoding/json.
+// Named struct types are registered in Schemas and referenced using $ref,
+// which also allows describing recursive types. Other named types, like slices and maps,
+// are inlined, unless they are recursive, like `type Tree []Tree`.
+type Reflector struct {
+	// The map to register named types in. The key is the name of the type. If nil, a new map is created on the first registration.
+	Schemas map[string]*Schema
+	// The prefix added to the name of a registered type to produce a $ref to it. Default value is [DefaultRefPrefix].
+	RefPrefix string
+	// The function that produces the name for a type to be registered. Default value is [DefaultNamer].
+	// If the name is already taken by another schema, the name is prefixed with the package name.
+	Namer func(t reflect.Type) string
+
+	// Names of the types registered by the reflector.
+	names map[reflect.Type]string
+	// Named non-struct types which schemas are being generated, to detect recursive ones.
+	inProgress map[reflect.Type]bool
+}
+
+// NewReflector creates a [Reflector] that registers named types in the document's Components.Schemas.
+func NewReflector(doc *OpenAPI) *Reflector {
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = make(map[string]*Schema)
+	}
+	return &Reflector{Schemas: doc.Components.Schemas}
+}
+
+// SchemaFor generates a standalone schema for the type T.
+//
+// Named struct types are placed into $defs of the returned schema.
+func SchemaFor[T any]() (*Schema, error) {
+	r := Reflector{RefPrefix: "#/$defs/"}
+	s, err := r.Reflect(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Schemas) > 0 {
+		s.Defs = r.Schemas
+	}
+	return s, nil
+}
+
+// Reflect generates a schema for the given type.
+func (r *Reflector) Reflect(t reflect.Type) (*Schema, error) {
+	if t == nil {
+		return nil, errors.New("cannot reflect nil type")
+	}
+	return r.reflect(t)
+}
+
+func (r *Reflector) reflect(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		s, err := r.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(s), nil
+	}
+	if s, ok := customSchema(t); ok {
+		applyHooks(s, t)
+		return s, nil
+	}
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		return r.register(t)
+	}
+	if t.Name() != "" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		return r.reflectNamed(t)
+	}
+	s, err := r.reflectKind(t)
+	if err != nil {
+		return nil, err
+	}
+	applyHooks(s, t)
+	return s, nil
+}
+
+// reflectNamed generates an inline schema for a named slice, array, or map type.
+// If the type refers to itself, it's registered in Schemas like a struct type.
+func (r *Reflector) reflectNamed(t reflect.Type) (*Schema, error) {
+	if name, ok := r.names[t]; ok {
+		return r.ref(name), nil
+	}
+	if r.inProgress[t] {
+		name, err := r.reserve(t)
+		if err != nil {
+			return nil, err
+		}
+		return r.ref(name), nil
+	}
+	if r.inProgress == nil {
+		r.inProgress = make(map[reflect.Type]bool)
+	}
+	r.inProgress[t] = true
+	s, err := r.reflectKind(t)
+	delete(r.inProgress, t)
+	name, recursive := r.names[t]
+	if err != nil {
+		if recursive {
+			delete(r.names, t)
+			delete(r.Schemas, name)
+		}
+		return nil, err
+	}
+	applyHooks(s, t)
+	if !recursive {
+		return s, nil
+	}
+	r.Schemas[name] = s
+	return r.ref(name), nil
+}
+
+// reflectKind generates the schema based only on the kind of the type.
+func (r *Reflector) reflectKind(t reflect.Type) (*Schema, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{TypeBoolean}}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: Types{TypeInteger}, Format: "int32"}, nil
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: Types{TypeInteger}, Format: "int64"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: Types{TypeInteger}, Minimum: "0"}, nil
+	case reflect.Float32:
+		return &Schema{Type: Types{TypeNumber}, Format: "float"}, nil
+	case reflect.Float64:
+		return &Schema{Type: Types{TypeNumber}, Format: "double"}, nil
+	case reflect.String:
+		return &Schema{Type: Types{TypeString}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !isMarshaler(t.Elem()) {
+			// encoding/json encodes byte slices as base64 strings.
+			return bytesSchema(), nil
+		}
+		items, err := r.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Types{TypeArray}, Items: items}, nil
+	case reflect.Array:
+		items, err := r.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		size := t.Len()
+		return &Schema{Type: Types{TypeArray}, Items: items, MinItems: size, MaxItems: &size}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := r.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Types{TypeObject}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return r.structSchema(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// register adds the schema for a named type into Schemas and returns a reference to it.
+func (r *Reflector) register(t reflect.Type) (*Schema, error) {
+	name, ok := r.names[t]
+	if !ok {
+		var err error
+		// Register the name before generating the schema to support recursive types.
+		name, err = r.reserve(t)
+		if err != nil {
+			return nil, err
+		}
+		s, err := r.structSchema(t)
+		if err != nil {
+			delete(r.names, t)
+			delete(r.Schemas, name)
+			return nil, err
+		}
+		applyHooks(s, t)
+		r.Schemas[name] = s
+	}
+	return r.ref(name), nil
+}
+
+// reserve picks the name for the type and registers an empty schema under it.
+func (r *Reflector) reserve(t reflect.Type) (string, error) {
+	if r.names == nil {
+		r.names = make(map[reflect.Type]string)
+	}
+	if r.Schemas == nil {
+		r.Schemas = make(map[string]*Schema)
+	}
+	name, err := r.name(t)
+	if err != nil {
+		return "", err
+	}
+	r.names[t] = name
+	r.Schemas[name] = &Schema{}
+	return name, nil
+}
+
+// ref returns a reference to the registered schema.
+func (r *Reflector) ref(name string) *Schema {
+	prefix := r.RefPrefix
+	if prefix == "" {
+		prefix = DefaultRefPrefix
+	}
+	return &Schema{Ref: prefix + name}
+}
+
+func (r *Reflector) structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{Type: Types{TypeObject}}
+	for _, f := range structFields(t, "json") {
+		fs, required, err := r.fieldSchema(f)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", f.goName, t, err)
+		}
+		if s.Properties == nil {
+			s.Properties = make(map[string]*Schema)
+		}
+		s.Properties[f.name] = fs
+		if required || !f.omit {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s, nil
+}
+
+// fieldSchema generates the schema for the struct field and reports if the tags mark the field as required.
+func (r *Reflector) fieldSchema(f structField) (*Schema, bool, error) {
+	s, err := r.reflect(f.typ)
+	if err != nil {
+		return nil, false, err
+	}
+	if f.quoted {
+		s = quoted(s, f.typ)
+	}
+	required, err := annotate(s, f)
+	if err != nil {
+		return nil, false, err
+	}
+	return s, required, nil
+}
+
+// nullable makes the schema also accept null.
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{TypeNull}}}}
+	}
+	if len(s.Type) > 0 && !slices.Contains(s.Type, TypeNull) {
+		s.Type = append(s.Type, TypeNull)
+	}
+	return s
+}
+
+// quoted adjusts the schema of a field with the "string" option in the json tag.
+//
+// The option makes encoding/json encode numbers and booleans as JSON strings.
+func quoted(s *Schema, t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+	default:
+		return s
+	}
+	for i, typ := range s.Type {
+		if typ != TypeNull {
+			s.Type[i] = TypeString
+		}
+	}
+	s.Minimum = ""
+	return s
+}
+
+// A struct field as seen by encoding/json.
+type structField struct {
+	// The name of the JSON object key.
+	name string
+	// The name of the field in Go.
+	goName string
+	// The field index sequence, as used by [reflect.Value.FieldByIndex].
+	index []int
+	typ   reflect.Type
+	// True if the name comes from the tag.
+	tagged bool
+	// True if the field has omitempty or omitzero option.
+	omit bool
+	// True if the field has the string option.
+	quoted bool
+	tag    reflect.StructTag
+}
+
+// structFields returns the fields of the struct that encoding/json encodes,
+// following the same rules for embedded structs and name conflicts.
+//
+// If nameKey isn't "json", the names are taken from the tag with that key when the field has it,
+// like `query:"page"`, and the json tag is used only for the fields without it.
+func structFields(t reflect.Type, nameKey string) []structField {
+	type level struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []structField
+	visited := make(map[reflect.Type]bool)
+	next := []level{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, lvl := range current {
+			if visited[lvl.typ] {
+				continue
+			}
+			visited[lvl.typ] = true
+			for i := range lvl.typ.NumField() {
+				sf := lvl.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if alt, ok := sf.Tag.Lookup(nameKey); ok && nameKey != "json" {
+					// The name from the other tag wins, even if the field is skipped in JSON.
+					altName, _, _ := strings.Cut(alt, ",")
+					if altName == "-" {
+						continue
+					}
+					if altName != "" {
+						_, opts, _ := strings.Cut(tag, ",")
+						tag = altName + "," + opts
+					}
+				}
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(lvl.index), i)
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, level{typ: ft, index: index})
+					continue
+				}
+				f := structField{
+					name:   name,
+					goName: sf.Name,
+					index:  index,
+					typ:    sf.Type,
+					tagged: name != "",
+					tag:    sf.Tag,
+				}
+				if name == "" {
+					f.name = sf.Name
+				}
+				for _, opt := range strings.Split(opts, ",") {
+					switch opt {
+					case "omitempty", "omitzero":
+						f.omit = true
+					case "string":
+						f.quoted = true
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	// Resolve name conflicts: the shallowest field wins,
+	// and if there are several of them, the only tagged one wins.
+	// If the conflict cannot be resolved, all the fields are dropped.
+	byName := make(map[string][]structField)
+	for _, f := range fields {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	result := make([]structField, 0, len(fields))
+	for _, f := range fields {
+		if dominant(f, byName[f.name]) {
+			result = append(result, f)
+		}
+	}
+	slices.SortFunc(result, func(a, b structField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return result
+}
+
+// dominant reports if the field is the one that encoding/json uses among all the fields with the same name.
+func dominant(f structField, rivals []structField) bool {
+	depth := len(f.index)
+	tagged := 0
+	for _, rival := range rivals {
+		if len(rival.index) < depth {
+			return false
+		}
+		if len(rival.index) == depth && rival.tagged {
+			tagged++
+		}
+	}
+	same := 0
+	for _, rival := range rivals {
+		if len(rival.index) == depth {
+			same++
+		}
+	}
+	if same == 1 {
+		return true
+	}
+	return f.tagged && tagged == 1
+}
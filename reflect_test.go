@@ -0,0 +1,124 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+type Base struct {
+	ID      int    `json:"id"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type Node struct {
+	Base
+	Name     string            `json:"name"`
+	Count    int64             `json:"count,string"`
+	Labels   map[string]string `json:"labels,omitzero"`
+	Children []Node            `json:"children"`
+	Parent   *Node             `json:"parent"`
+	Point    [2]float64        `json:"point"`
+	Ignored  string            `json:"-"`
+	Dash     bool              `json:"-,"`
+	private  int
+}
+
+func TestReflector_Reflect(t *testing.T) {
+	doc := openapi.OpenAPI{}
+	r := openapi.NewReflector(&doc)
+	s, err := r.Reflect(reflect.TypeFor[Node]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Ref != "#/components/schemas/Node" {
+		t.Errorf("unexpected ref: %s", s.Ref)
+	}
+	got, err := json.Marshal(doc.Components.Schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Node": {
+		"type": "object",
+		"required": ["id", "name", "count", "children", "parent", "point", "-"],
+		"properties": {
+			"id": {"type": "integer", "format": "int64"},
+			"comment": {"type": "string"},
+			"name": {"type": "string"},
+			"count": {"type": "string", "format": "int64"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}},
+			"parent": {"anyOf": [{"$ref": "#/components/schemas/Node"}, {"type": "null"}]},
+			"point": {"type": "array", "items": {"type": "number", "format": "double"}, "minItems": 2, "maxItems": 2},
+			"-": {"type": "boolean"}
+		}
+	}}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected schemas: %s", got)
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	s, err := openapi.SchemaFor[[]*Base]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+		"type": "array",
+		"items": {"anyOf": [{"$ref": "#/$defs/Base"}, {"type": "null"}]},
+		"$defs": {"Base": {
+			"type": "object",
+			"required": ["id"],
+			"properties": {
+				"id": {"type": "integer", "format": "int64"},
+				"comment": {"type": "string"}
+			}
+		}}
+	}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected schema: %s", got)
+	}
+}
+
+type Tree []Tree
+
+type Labels map[string]string
+
+func TestSchemaFor_RecursiveNamed(t *testing.T) {
+	s, err := openapi.SchemaFor[struct {
+		Tree   Tree   `json:"tree"`
+		Labels Labels `json:"labels"`
+	}]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+		"type": "object",
+		"required": ["tree", "labels"],
+		"properties": {
+			"tree": {"$ref": "#/$defs/Tree"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+		},
+		"$defs": {"Tree": {"type": "array", "items": {"$ref": "#/$defs/Tree"}}}
+	}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected schema: %s", got)
+	}
+}
+
+func TestSchemaFor_Unsupported(t *testing.T) {
+	_, err := openapi.SchemaFor[struct{ C chan int }]()
+	if err == nil {
+		t.Error("expected an error for a channel field")
+	}
+}
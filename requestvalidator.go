@@ -0,0 +1,355 @@
+package openapi
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxBodySize is the size limit of request bodies used by [ValidateRequests] by default.
+const DefaultMaxBodySize = 10 << 20
+
+// RequestValidation configures the middleware returned by [RequestValidation.Middleware].
+type RequestValidation struct {
+	// The maximal size of request bodies in bytes. Larger bodies get "413 Content Too Large"
+	// with [Problem] details. If zero, [DefaultMaxBodySize] is used. If negative, the size isn't limited.
+	MaxBodySize int64
+}
+
+// ValidateRequests returns a middleware that checks incoming requests against the operations of the document.
+//
+// The request is matched to the operation by its path and method. Then its path, query, header,
+// and cookie parameters are checked against the operation parameters, and the body against
+// the schema of its media type in the request body. Formats of the schemas are asserted.
+// An invalid request gets a "400 Bad Request" response with [Problem] details listing
+// each violation with a pointer to the invalid field. Requests that don't match
+// any operation are passed to the next handler as is.
+//
+// The body is read into memory to be validated, up to [DefaultMaxBodySize].
+// Use [RequestValidation.Middleware] to change the limit.
+//
+// It panics if the document can't be used for validation, like when a reference doesn't resolve.
+// Use [OpenAPI.Validate] to find such problems beforehand.
+func ValidateRequests(doc *OpenAPI) func(http.Handler) http.Handler {
+	return RequestValidation{}.Middleware(doc)
+}
+
+// Middleware returns a middleware like [ValidateRequests] that uses the options.
+func (o RequestValidation) Middleware(doc *OpenAPI) func(http.Handler) http.Handler {
+	v, err := newRequestValidator(doc)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: validate requests: %v", err))
+	}
+	v.maxBodySize = cmp.Or(o.MaxBodySize, DefaultMaxBodySize)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			errs, err := v.validate(w, r)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeProblem(w, Problem{
+					Status: http.StatusRequestEntityTooLarge,
+					Detail: fmt.Sprintf("the request body is larger than %d bytes", tooLarge.Limit),
+				})
+				return
+			}
+			if err != nil {
+				writeProblem(w, Problem{
+					Status: http.StatusBadRequest,
+					Detail: fmt.Sprintf("cannot read the request body: %v", err),
+				})
+				return
+			}
+			if len(errs) > 0 {
+				writeProblem(w, Problem{
+					Status: http.StatusBadRequest,
+					Detail: "the request doesn't match the API description",
+					Errors: errs,
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestValidator checks requests against the operations of the document.
+type requestValidator struct {
+	doc     *OpenAPI
+	router  *Router
+	schemas *SchemaValidator
+	// Resolved parameters and request bodies of the operations by their paths and methods.
+	operations map[string]map[string]requestOperation
+	// The size limit of request bodies. If negative, the size isn't limited.
+	maxBodySize int64
+}
+
+// The parts of an operation needed to validate its requests.
+type requestOperation struct {
+	params []Parameter
+	body   RequestBody
+	// If false, the operation has no request body and any body is allowed.
+	hasBody bool
+}
+
+func newRequestValidator(doc *OpenAPI) (*requestValidator, error) {
+	schemas, err := NewSchemaValidator(doc)
+	if err != nil {
+		return nil, err
+	}
+	schemas.AssertFormat = true
+	v := &requestValidator{
+		doc:        doc,
+		router:     NewRouter(doc),
+		schemas:    schemas,
+		operations: make(map[string]map[string]requestOperation),
+	}
+	for path, item := range doc.Paths.Items {
+		ops := make(map[string]requestOperation)
+		for method, op := range item.operations() {
+			if isZero(op) {
+				continue
+			}
+			reqOp, err := v.resolveOperation(item, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			ops[strings.ToUpper(method)] = reqOp
+		}
+		v.operations[path] = ops
+	}
+	return v, nil
+}
+
+// resolveOperation resolves the references of the operation
+// and merges its parameters with the ones of the path item.
+func (v *requestValidator) resolveOperation(item PathItem, op Operation) (requestOperation, error) {
+	var result requestOperation
+	type key struct {
+		name string
+		in   Location
+	}
+	index := make(map[key]int)
+	for _, param := range slices.Concat(item.Parameters, op.Parameters) {
+		p, err := param.Resolve(v.doc)
+		if err != nil {
+			return result, err
+		}
+		k := key{p.Name, p.In}
+		if p.In == InHeader {
+			k.name = strings.ToLower(p.Name)
+		}
+		if i, ok := index[k]; ok {
+			// The operation parameter overrides the path item one.
+			result.params[i] = p
+			continue
+		}
+		index[k] = len(result.params)
+		result.params = append(result.params, p)
+	}
+	if !op.RequestBody.IsZero() {
+		body, err := op.RequestBody.Resolve(v.doc)
+		if err != nil {
+			return result, err
+		}
+		result.body = body
+		result.hasBody = true
+	}
+	return result, nil
+}
+
+// validate checks the request and returns the violations.
+// The request body is read and replaced with a copy.
+// The error is returned if the body can't be read, like when it's too large.
+func (v *requestValidator) validate(w http.ResponseWriter, r *http.Request) (FieldErrors, error) {
+	route, err := v.router.Match(r)
+	if err != nil {
+		return nil, nil
+	}
+	op := v.operations[route.Path][route.Method]
+	var errs FieldErrors
+	for _, p := range op.params {
+		errs = append(errs, v.parameter(r, p, route.rawPathParams)...)
+	}
+	if op.hasBody {
+		data, err := v.readBody(w, r)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, v.body(r, op.body, data)...)
+	}
+	return errs, nil
+}
+
+// parameter checks the parameter of the request. The path parameters are escaped.
+func (v *requestValidator) parameter(r *http.Request, p Parameter, pathParams map[string]string) FieldErrors {
+	ptr := at("", string(p.In), p.Name)
+	raw, ok := rawParameter(r, p, pathParams)
+	var value any
+	var err error
+	if ok {
+		value, ok, err = p.Decode(v.doc, raw)
+		if err != nil {
+			return FieldErrors{{Pointer: ptr, Detail: err.Error()}}
+		}
+	}
+	if !ok {
+		if p.RequiredOrDefault() {
+			return FieldErrors{{Pointer: ptr, Detail: fmt.Sprintf("the %s parameter is required", p.In)}}
+		}
+		return nil
+	}
+	schema := p.Schema
+	for _, media := range p.Content {
+		schema = media.Schema
+	}
+	return v.check(ptr, schema, value)
+}
+
+// rawParameter returns the part of the request to decode the parameter from, as [Parameter.Decode] expects it.
+// It returns false if the location of the parameter is missing in the request.
+func rawParameter(r *http.Request, p Parameter, pathParams map[string]string) (string, bool) {
+	switch p.In {
+	case InPath:
+		value, ok := pathParams[p.Name]
+		return value, ok
+	case InQuery:
+		return r.URL.RawQuery, true
+	case InHeader:
+		switch http.CanonicalHeaderKey(p.Name) {
+		case "Accept", "Content-Type", "Authorization":
+			// These headers are described by other fields and the parameter is ignored.
+			return "", false
+		}
+		values := r.Header.Values(p.Name)
+		return strings.Join(values, ","), len(values) > 0
+	case InCookie:
+		values := r.Header.Values("Cookie")
+		return strings.Join(values, "; "), len(values) > 0
+	}
+	return "", false
+}
+
+// readBody reads the request body up to the size limit and replaces it with a copy.
+func (v *requestValidator) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body := r.Body
+	if v.maxBodySize >= 0 {
+		body = http.MaxBytesReader(w, r.Body, v.maxBodySize)
+	}
+	data, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// body checks the request body against the schema of its media type.
+func (v *requestValidator) body(r *http.Request, body RequestBody, data []byte) FieldErrors {
+	contentType := r.Header.Get("Content-Type")
+	if len(data) == 0 && contentType == "" {
+		if body.Required {
+			return FieldErrors{{Pointer: "/body", Detail: "the request body is required"}}
+		}
+		return nil
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_, media, ok := matchMediaType(body.Content, contentType)
+	if !ok {
+		return FieldErrors{{
+			Pointer: "/header/Content-Type",
+			Detail:  fmt.Sprintf("unsupported media type %q, expected one of %s", contentType, strings.Join(sortedKeys(body.Content), ", ")),
+		}}
+	}
+	value, ok, err := v.decodeBody(contentType, media, data)
+	if err != nil {
+		return FieldErrors{{Pointer: "/body", Detail: err.Error()}}
+	}
+	if !ok {
+		return nil
+	}
+	return v.check("/body", media.Schema, value)
+}
+
+// decodeBody decodes the body of the content type into a generic JSON value to validate against the schema.
+// It returns false if the content type can't be validated.
+func (v *requestValidator) decodeBody(contentType string, media MediaType, data []byte) (any, bool, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case isJSONMediaType(mediaType):
+		var value any
+		err := decodeJSON(data, &value)
+		if err != nil {
+			return nil, false, errors.New("the body is not valid JSON")
+		}
+		return value, true, nil
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, false, errors.New("the body is not a valid form")
+		}
+		schema := v.doc.resolveSchema(media.Schema)
+		var names []string
+		if schema != nil {
+			names = sortedKeys(schema.Properties)
+		}
+		for _, name := range sortedKeys(form) {
+			// Skip the declared properties and the deepObject keys, like "color[R]".
+			prefix, _, _ := strings.Cut(name, "[")
+			if schema == nil || schema.Properties[prefix] == nil {
+				names = append(names, name)
+			}
+		}
+		obj := make(map[string]any, len(names))
+		for _, name := range names {
+			enc := media.Encoding[name]
+			p := Parameter{
+				Name:          name,
+				In:            InQuery,
+				Style:         enc.Style,
+				Explode:       enc.Explode,
+				AllowReserved: enc.AllowReserved,
+				Schema:        v.doc.propertySchema(schema, name),
+			}
+			value, ok, err := p.Decode(v.doc, string(data))
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				obj[name] = value
+			}
+		}
+		return obj, true, nil
+	}
+	return nil, false, nil
+}
+
+// check validates the value against the schema
+// and converts the schema errors into field errors under the pointer.
+func (v *requestValidator) check(ptr string, schema *Schema, value any) FieldErrors {
+	err := v.schemas.Validate(schema, value)
+	if err == nil {
+		return nil
+	}
+	var schemaErrs SchemaErrors
+	if !errors.As(err, &schemaErrs) {
+		return FieldErrors{{Pointer: ptr, Detail: err.Error()}}
+	}
+	errs := make(FieldErrors, len(schemaErrs))
+	for i, e := range schemaErrs {
+		errs[i] = FieldError{Pointer: ptr + e.InstanceLocation, Detail: e.Message}
+	}
+	return errs
+}
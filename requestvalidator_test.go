@@ -0,0 +1,289 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func petsAPI() *openapi.OpenAPI {
+	petID := openapi.Inline(openapi.Parameter{
+		Name:     "id",
+		In:       "path",
+		Required: ptr(true),
+		Schema:   &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}, Minimum: "1"},
+	})
+	return &openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets": {
+				Get: openapi.Operation{
+					Parameters: []openapi.RefOr[openapi.Parameter]{
+						openapi.Ref[openapi.Parameter]("#/components/parameters/Limit"),
+						openapi.Inline(openapi.Parameter{
+							Name:   "tags",
+							In:     "query",
+							Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeArray}, Items: &openapi.Schema{Type: openapi.Types{openapi.TypeString}}, MaxItems: ptr(2)},
+						}),
+						openapi.Inline(openapi.Parameter{
+							Name:     "X-Request-ID",
+							In:       "header",
+							Required: ptr(true),
+							Schema:   &openapi.Schema{Type: openapi.Types{openapi.TypeString}, Format: "uuid"},
+						}),
+						openapi.Inline(openapi.Parameter{
+							Name: "filter",
+							In:   "query",
+							Content: map[string]openapi.MediaType{
+								"application/json": {Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeObject}, Required: []string{"kind"}}},
+							},
+						}),
+					},
+					Responses: openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "The pets"})},
+				},
+				Post: openapi.Operation{
+					RequestBody: openapi.Inline(openapi.RequestBody{
+						Required: true,
+						Content: map[string]openapi.MediaType{
+							"application/json":                  {Schema: &openapi.Schema{Ref: "#/components/schemas/Pet"}},
+							"application/x-www-form-urlencoded": {Schema: &openapi.Schema{Ref: "#/components/schemas/Pet"}},
+						},
+					}),
+					Responses: openapi.Responses{Created: openapi.Inline(openapi.Response{Description: "The new pet"})},
+				},
+			},
+			"/pets/{id}": {
+				Parameters: []openapi.RefOr[openapi.Parameter]{petID},
+				Get: openapi.Operation{
+					Parameters: []openapi.RefOr[openapi.Parameter]{
+						openapi.Inline(openapi.Parameter{
+							Name:   "session",
+							In:     "cookie",
+							Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeString}, MinLength: 8},
+						}),
+					},
+					Responses: openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "The pet"})},
+				},
+			},
+			"/pets/mine": {
+				Get: openapi.Operation{
+					Responses: openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "My pets"})},
+				},
+			},
+		}},
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Pet": {
+					Type:     openapi.Types{openapi.TypeObject},
+					Required: []string{"name"},
+					Properties: map[string]*openapi.Schema{
+						"name": {Type: openapi.Types{openapi.TypeString}, MinLength: 1},
+						"age":  {Type: openapi.Types{openapi.TypeInteger}, Minimum: "0"},
+					},
+				},
+			},
+			Parameters: map[string]openapi.RefOr[openapi.Parameter]{
+				"Limit": openapi.Inline(openapi.Parameter{
+					Name:   "limit",
+					In:     "query",
+					Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}, Maximum: "100"},
+				}),
+			},
+		},
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	const requestID = "123e4567-e89b-12d3-a456-426614174000"
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		header      map[string]string
+		// Pointers of the expected errors.
+		want []string
+	}{
+		{
+			name:   "valid query",
+			method: "GET",
+			target: "/pets?limit=10&tags=a&tags=b&filter=%7B%22kind%22%3A%22cat%22%7D",
+			header: map[string]string{"X-Request-ID": requestID},
+		},
+		{
+			name:   "invalid query",
+			method: "GET",
+			target: "/pets?limit=1000&tags=a&tags=b&tags=c&filter=%7B%7D",
+			header: map[string]string{"X-Request-ID": requestID},
+			want:   []string{"/query/limit", "/query/tags", "/query/filter"},
+		},
+		{
+			name:   "not a number",
+			method: "GET",
+			target: "/pets?limit=ten",
+			header: map[string]string{"X-Request-ID": requestID},
+			want:   []string{"/query/limit"},
+		},
+		{
+			name:   "missing required header",
+			method: "GET",
+			target: "/pets",
+			want:   []string{"/header/X-Request-ID"},
+		},
+		{
+			name:   "header format",
+			method: "GET",
+			target: "/pets",
+			header: map[string]string{"X-Request-ID": "nope"},
+			want:   []string{"/header/X-Request-ID"},
+		},
+		{
+			name:   "path parameter",
+			method: "GET",
+			target: "/pets/0",
+			want:   []string{"/path/id"},
+		},
+		{
+			name:   "cookie parameter",
+			method: "GET",
+			target: "/pets/1",
+			header: map[string]string{"Cookie": "session=short"},
+			want:   []string{"/cookie/session"},
+		},
+		{
+			name:   "concrete path",
+			method: "GET",
+			target: "/pets/mine",
+		},
+		{
+			name:        "valid JSON body",
+			method:      "POST",
+			target:      "/pets",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name": "Rex", "age": 3}`,
+		},
+		{
+			name:        "invalid JSON body",
+			method:      "POST",
+			target:      "/pets",
+			contentType: "application/json",
+			body:        `{"name": "", "age": -1}`,
+			want:        []string{"/body/age", "/body/name"},
+		},
+		{
+			name:        "malformed JSON body",
+			method:      "POST",
+			target:      "/pets",
+			contentType: "application/json",
+			body:        `{"name":`,
+			want:        []string{"/body"},
+		},
+		{
+			name:        "form body",
+			method:      "POST",
+			target:      "/pets",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "name=Rex&age=old",
+			want:        []string{"/body/age"},
+		},
+		{
+			name:   "missing body",
+			method: "POST",
+			target: "/pets",
+			want:   []string{"/body"},
+		},
+		{
+			name:        "unsupported media type",
+			method:      "POST",
+			target:      "/pets",
+			contentType: "text/plain",
+			body:        "Rex",
+			want:        []string{"/header/Content-Type"},
+		},
+		{
+			name:   "unknown path",
+			method: "GET",
+			target: "/owners",
+		},
+		{
+			name:   "undeclared method",
+			method: "DELETE",
+			target: "/pets",
+		},
+	}
+	handler := openapi.ValidateRequests(petsAPI())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body must still be readable by the handler.
+		_, _ = io.Copy(w, r.Body)
+	}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if tt.want == nil {
+				if rec.Code != http.StatusOK || rec.Body.String() != tt.body {
+					t.Errorf("unexpected response %d: %s", rec.Code, rec.Body)
+				}
+				return
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("unexpected status code %d", rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("unexpected content type %q", ct)
+			}
+			var problem openapi.Problem
+			err := json.NewDecoder(rec.Body).Decode(&problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range problem.Errors {
+				got = append(got, e.Pointer)
+			}
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if problem.Status != http.StatusBadRequest || !slices.Equal(got, tt.want) {
+				t.Errorf("expected errors at %q, got %+v", tt.want, problem)
+			}
+		})
+	}
+}
+
+func TestValidateRequests_MaxBodySize(t *testing.T) {
+	handler := openapi.RequestValidation{MaxBodySize: 16}.Middleware(petsAPI())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	}))
+	for _, tt := range []struct {
+		body   string
+		status int
+	}{
+		{`{"name": "Rex"}`, http.StatusOK},
+		{`{"name": "Rex", "age": 3}`, http.StatusRequestEntityTooLarge},
+	} {
+		req := httptest.NewRequest("POST", "/pets", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: unexpected status code %d", tt.body, rec.Code)
+		}
+		if tt.status != http.StatusOK && rec.Header().Get("Content-Type") != "application/problem+json" {
+			t.Errorf("%s: unexpected content type %q", tt.body, rec.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Indices of the fields of [Responses] by the status code they hold.
+var responseFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeFor[Responses]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+// IsValidStatusCode reports if the string can be used as a key in [Responses].
+//
+// Valid keys are "default", three-digit HTTP status codes from 100 to 599,
+// and ranges of status codes from "1XX" to "5XX".
+func IsValidStatusCode(code string) bool {
+	if code == "default" {
+		return true
+	}
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return false
+	}
+	if code[1:] == "XX" {
+		return true
+	}
+	return isDigit(code[1]) && isDigit(code[2])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// field returns the dedicated field for the status code, if there is one.
+func (r *Responses) field(code string) (*RefOr[Response], bool) {
+	i, ok := responseFields[code]
+	if !ok {
+		return nil, false
+	}
+	return reflect.ValueOf(r).Elem().Field(i).Addr().Interface().(*RefOr[Response]), true
+}
+
+// Get returns the response for the status code, like "200", "4XX", or "default".
+//
+// If the dedicated field for the status code, like [Responses.OK], is zero,
+// the response is looked up in Codes.
+func (r *Responses) Get(code string) (RefOr[Response], bool) {
+	if f, ok := r.field(code); ok && !f.IsZero() {
+		return *f, true
+	}
+	resp, ok := r.Codes[code]
+	return resp, ok
+}
+
+// Match returns the response that documents the status code of an actual response:
+// the one for the status code, like "404", or for its range, like "4XX", or the default one.
+func (r *Responses) Match(status int) (RefOr[Response], bool) {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if resp, ok := r.Get(key); ok {
+			return resp, true
+		}
+	}
+	return RefOr[Response]{}, false
+}
+
+// Set sets the response for the status code, like "200", "4XX", or "default".
+//
+// The response is stored in the dedicated field if there is one for the status code and in Codes otherwise.
+func (r *Responses) Set(code string, resp RefOr[Response]) error {
+	if !IsValidStatusCode(code) {
+		return fmt.Errorf("invalid response status code %q", code)
+	}
+	if f, ok := r.field(code); ok {
+		*f = resp
+		delete(r.Codes, code)
+		return nil
+	}
+	if r.Codes == nil {
+		r.Codes = make(map[string]RefOr[Response])
+	}
+	r.Codes[code] = resp
+	return nil
+}
+
+// All iterates over all declared responses, both from the dedicated fields and from Codes.
+//
+// The responses are sorted by the status code. Each range, like "4XX",
+// goes after the status codes it covers, and "default" goes last.
+func (r *Responses) All() iter.Seq2[string, RefOr[Response]] {
+	return func(yield func(string, RefOr[Response]) bool) {
+		codes := make([]string, 0, len(r.Codes)+4)
+		for code, i := range responseFields {
+			if _, inCodes := r.Codes[code]; !inCodes && !reflect.ValueOf(r).Elem().Field(i).IsZero() {
+				codes = append(codes, code)
+			}
+		}
+		for code := range r.Codes {
+			codes = append(codes, code)
+		}
+		slices.SortFunc(codes, compareStatusCodes)
+		for _, code := range codes {
+			resp, _ := r.Get(code)
+			if !yield(code, resp) {
+				return
+			}
+		}
+	}
+}
+
+// compareStatusCodes orders response keys: status codes first, ranges after the codes they cover, and default last.
+func compareStatusCodes(a, b string) int {
+	return cmp.Or(
+		cmp.Compare(boolToInt(a == "default"), boolToInt(b == "default")),
+		cmp.Compare(a[0], b[0]),
+		cmp.Compare(boolToInt(strings.HasSuffix(a, "XX")), boolToInt(strings.HasSuffix(b, "XX"))),
+		cmp.Compare(a, b),
+	)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (r Responses) MarshalJSON() ([]byte, error) {
+	err := checkExtensions(r.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	for code := range r.Codes {
+		if !IsValidStatusCode(code) {
+			return nil, fmt.Errorf("invalid response status code %q", code)
+		}
+		if f, ok := r.field(code); ok && !f.IsZero() {
+			return nil, fmt.Errorf("response for status code %s is defined twice", code)
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for code, resp := range r.All() {
+		raw, err := json.Marshal(resp)
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %w", code, err)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "%q:", code)
+		buf.Write(raw)
+	}
+	buf.WriteByte('}')
+	return marshalInline(json.RawMessage(buf.Bytes()), r.Extensions)
+}
+
+func (r *Responses) UnmarshalJSON(data []byte) error {
+	type raw Responses
+	*r = Responses{}
+	unknown, err := unmarshalInline(data, (*raw)(r))
+	if err != nil {
+		return err
+	}
+	for key, val := range unknown {
+		if strings.HasPrefix(key, "x-") {
+			var ext any
+			err = decodeJSON(val, &ext)
+			if err != nil {
+				return err
+			}
+			if r.Extensions == nil {
+				r.Extensions = make(map[string]any)
+			}
+			r.Extensions[key] = ext
+			continue
+		}
+		var resp RefOr[Response]
+		err = decodeJSON(val, &resp)
+		if err != nil {
+			return fmt.Errorf("response %s: %w", key, err)
+		}
+		err = r.Set(key, resp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
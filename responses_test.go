@@ -0,0 +1,120 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestResponses(t *testing.T) {
+	resps := openapi.Responses{
+		OK:      openapi.Inline(openapi.Response{Description: "OK"}),
+		Default: openapi.Ref[openapi.Response]("#/components/responses/Error"),
+	}
+	for _, code := range []string{"420", "4XX", "599", "201", "2XX"} {
+		err := resps.Set(code, openapi.Inline(openapi.Response{Description: code}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, code := range []string{"600", "099", "4xx", "20", "x-foo", "DEFAULT"} {
+		err := resps.Set(code, openapi.Inline(openapi.Response{}))
+		if err == nil {
+			t.Errorf("expected an error for %q", code)
+		}
+	}
+	if resps.Created.Value.Description != "201" {
+		t.Error("response for 201 must be stored in the dedicated field")
+	}
+	resp, ok := resps.Get("420")
+	if !ok || resp.Value.Description != "420" {
+		t.Errorf("unexpected response for 420: %+v", resp)
+	}
+	if _, ok := resps.Get("404"); ok {
+		t.Error("response for 404 is not declared")
+	}
+
+	for status, want := range map[int]string{201: "201", 204: "2XX", 420: "420", 404: "4XX", 302: ""} {
+		resp, _ := resps.Match(status)
+		if status == 302 {
+			if resp.Ref != "#/components/responses/Error" {
+				t.Errorf("expected the default response for 302, got %+v", resp)
+			}
+			continue
+		}
+		if resp.Value.Description != want {
+			t.Errorf("unexpected response for %d: %+v", status, resp)
+		}
+	}
+
+	var codes []string
+	for code := range resps.All() {
+		codes = append(codes, code)
+	}
+	want := []string{"200", "201", "2XX", "420", "4XX", "599", "default"}
+	if !slices.Equal(codes, want) {
+		t.Errorf("got %v, want %v", codes, want)
+	}
+
+	resps.Extensions = map[string]any{"x-note": "hi"}
+	got, err := json.Marshal(resps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{` +
+		`"200":{"description":"OK"},` +
+		`"201":{"description":"201"},` +
+		`"2XX":{"description":"2XX"},` +
+		`"420":{"description":"420"},` +
+		`"4XX":{"description":"4XX"},` +
+		`"599":{"description":"599"},` +
+		`"default":{"$ref":"#/components/responses/Error"},` +
+		`"x-note":"hi"}`
+	if string(got) != wantJSON {
+		t.Errorf("unexpected JSON: %s", got)
+	}
+
+	var parsed openapi.Responses
+	err = json.Unmarshal(got, &parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Codes["420"].Value.Description != "420" || parsed.Extensions["x-note"] != "hi" {
+		t.Errorf("unexpected responses: %+v", parsed)
+	}
+}
+
+func TestResponses_Invalid(t *testing.T) {
+	var resps openapi.Responses
+	err := json.Unmarshal([]byte(`{"600": {"description": "too big"}}`), &resps)
+	if err == nil {
+		t.Error("expected an error for an invalid status code")
+	}
+	resps = openapi.Responses{
+		OK:    openapi.Inline(openapi.Response{Description: "OK"}),
+		Codes: map[string]openapi.RefOr[openapi.Response]{"200": openapi.Inline(openapi.Response{Description: "dup"})},
+	}
+	_, err = json.Marshal(resps)
+	if err == nil {
+		t.Error("expected an error for a duplicate status code")
+	}
+}
+
+func TestResponses_DedicatedCodeInCodes(t *testing.T) {
+	resps := openapi.Responses{
+		Codes: map[string]openapi.RefOr[openapi.Response]{"200": openapi.Inline(openapi.Response{Description: "OK"})},
+	}
+	resp, ok := resps.Get("200")
+	if !ok || resp.Value.Description != "OK" {
+		t.Errorf("unexpected response for 200: %+v", resp)
+	}
+	got, err := json.Marshal(resps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"200":{"description":"OK"}}` {
+		t.Errorf("unexpected JSON: %s", got)
+	}
+}
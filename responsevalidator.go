@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// ResponseValidation configures what [ValidateResponses] does with invalid responses.
+// The actions can be combined.
+type ResponseValidation struct {
+	// If not nil, the violations are logged as errors.
+	Logger *slog.Logger
+	// If not nil, the violations fail the test. It's usually [testing.TB].
+	T TestingT
+	// If true, the invalid response is replaced with "500 Internal Server Error"
+	// with [Problem] details listing the violations.
+	Replace bool
+}
+
+// TestingT is the part of [testing.TB] used to fail a test.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// ValidateResponses returns a middleware that checks the responses of the handler
+// against the operations of the document. It's meant for tests and staging environments.
+//
+// The status code must be declared by the operation responses, explicitly, by a range, or by default.
+// The Content-Type must match a media type of the response and the body must match its schema.
+// The required headers must be present and all declared headers must match their schemas.
+// Responses to requests that don't match any operation aren't checked.
+//
+// The response is buffered until the handler returns, so streaming responses are delayed.
+// It panics if the document can't be used for validation, like when a reference doesn't resolve.
+func ValidateResponses(doc *OpenAPI, opts ResponseValidation) func(http.Handler) http.Handler {
+	v, err := newRequestValidator(doc)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: validate responses: %v", err))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, ok := v.operation(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec := &responseRecorder{header: make(http.Header)}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			errs := v.response(r, op, rec)
+			if len(errs) > 0 {
+				if opts.Logger != nil {
+					opts.Logger.ErrorContext(r.Context(), "invalid response",
+						"method", r.Method, "path", r.URL.Path, "status", rec.status, "error", errs)
+				}
+				if opts.T != nil {
+					opts.T.Helper()
+					opts.T.Errorf("%s %s: invalid response %d:\n%v", r.Method, r.URL.Path, rec.status, errs)
+				}
+				if opts.Replace {
+					writeProblem(w, Problem{
+						Status: http.StatusInternalServerError,
+						Detail: "the response doesn't match the API description",
+						Errors: errs,
+					})
+					return
+				}
+			}
+			for key, values := range rec.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+// responseRecorder buffers the response of a handler.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(data)
+}
+
+// operation finds the operation for the request.
+func (v *requestValidator) operation(r *http.Request) (Operation, bool) {
+	route, err := v.router.Match(r)
+	if err != nil {
+		return Operation{}, false
+	}
+	return route.Operation, true
+}
+
+// response checks the recorded response to the request and returns the violations.
+func (v *requestValidator) response(r *http.Request, op Operation, rec *responseRecorder) FieldErrors {
+	ref, ok := op.Responses.Match(rec.status)
+	if !ok {
+		return FieldErrors{{Pointer: "/status", Detail: fmt.Sprintf("the status code %d is not declared", rec.status)}}
+	}
+	resp, err := ref.Resolve(v.doc)
+	if err != nil {
+		return FieldErrors{{Pointer: "/status", Detail: fmt.Sprintf("cannot resolve the response for %d: %v", rec.status, err)}}
+	}
+	var errs FieldErrors
+	for _, name := range sortedKeys(resp.Headers) {
+		if http.CanonicalHeaderKey(name) == "Content-Type" {
+			continue
+		}
+		h, err := resp.Headers[name].Resolve(v.doc)
+		if err != nil {
+			errs = append(errs, FieldError{Pointer: at("/header", name), Detail: err.Error()})
+			continue
+		}
+		errs = append(errs, v.responseHeader(rec.header, name, h)...)
+	}
+	if r.Method == http.MethodHead {
+		return errs
+	}
+	return append(errs, v.responseBody(resp, rec)...)
+}
+
+// responseHeader checks the header of the response.
+func (v *requestValidator) responseHeader(header http.Header, name string, h Header) FieldErrors {
+	ptr := at("/header", name)
+	values := header.Values(name)
+	if len(values) == 0 {
+		if h.RequiredOrDefault() {
+			return FieldErrors{{Pointer: ptr, Detail: "the header is required"}}
+		}
+		return nil
+	}
+	p := Parameter{Name: name, In: InHeader, Style: h.Style, Explode: h.Explode, Schema: h.Schema, Content: h.Content}
+	value, _, err := p.Decode(v.doc, strings.Join(values, ","))
+	if err != nil {
+		return FieldErrors{{Pointer: ptr, Detail: err.Error()}}
+	}
+	schema := h.Schema
+	for _, media := range h.Content {
+		schema = media.Schema
+	}
+	return v.check(ptr, schema, value)
+}
+
+// responseBody checks the body of the response against the schema of its media type.
+func (v *requestValidator) responseBody(resp Response, rec *responseRecorder) FieldErrors {
+	data := rec.body.Bytes()
+	if len(resp.Content) == 0 {
+		if len(data) > 0 {
+			return FieldErrors{{Pointer: "/body", Detail: "the response has a body but no content is declared"}}
+		}
+		return nil
+	}
+	contentType := rec.header.Get("Content-Type")
+	if len(data) == 0 {
+		// Responses with these status codes can't have a body.
+		if rec.status < 200 || rec.status == http.StatusNoContent || rec.status == http.StatusNotModified {
+			return nil
+		}
+		if contentType == "" {
+			return FieldErrors{{Pointer: "/body", Detail: "the response has no body but content is declared"}}
+		}
+	}
+	if contentType == "" {
+		// net/http detects the content type the same way.
+		contentType = http.DetectContentType(data)
+	}
+	_, media, ok := matchMediaType(resp.Content, contentType)
+	if !ok {
+		return FieldErrors{{
+			Pointer: "/header/Content-Type",
+			Detail:  fmt.Sprintf("undeclared media type %q, expected one of %s", contentType, strings.Join(sortedKeys(resp.Content), ", ")),
+		}}
+	}
+	value, ok, err := v.decodeBody(contentType, media, data)
+	if err != nil {
+		return FieldErrors{{Pointer: "/body", Detail: err.Error()}}
+	}
+	if !ok {
+		return nil
+	}
+	return v.check("/body", media.Schema, value)
+}
@@ -0,0 +1,189 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+// testingT records the failures reported by the middleware.
+type testingT struct {
+	errs []string
+}
+
+func (t *testingT) Helper() {}
+
+func (t *testingT) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func TestValidateResponses(t *testing.T) {
+	doc := petsAPI()
+	doc.Paths.Items["/pets/{id}"] = openapi.PathItem{
+		Get: openapi.Operation{
+			Responses: openapi.Responses{
+				OK: openapi.Inline(openapi.Response{
+					Description: "The pet",
+					Headers: map[string]openapi.RefOr[openapi.Header]{
+						"X-Rate-Limit": openapi.Inline(openapi.Header{Required: ptr(true), Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}}}),
+					},
+					Content: map[string]openapi.MediaType{
+						"application/json": {Schema: &openapi.Schema{Ref: "#/components/schemas/Pet"}},
+					},
+				}),
+				NoContent: openapi.Inline(openapi.Response{Description: "No pet"}),
+				Codes: map[string]openapi.RefOr[openapi.Response]{
+					"4XX": openapi.Inline(openapi.Response{
+						Description: "An error",
+						Content:     map[string]openapi.MediaType{"application/problem+json": {}},
+					}),
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		header      map[string]string
+		// Pointers of the expected errors.
+		want []string
+	}{
+		{
+			name:        "valid",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"name": "Rex"}`,
+			header:      map[string]string{"X-Rate-Limit": "10"},
+		},
+		{
+			name:        "invalid body and header",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"age": -1}`,
+			header:      map[string]string{"X-Rate-Limit": "many"},
+			want:        []string{"/header/X-Rate-Limit", "/body", "/body/age"},
+		},
+		{
+			name:        "missing required header",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"name": "Rex"}`,
+			want:        []string{"/header/X-Rate-Limit"},
+		},
+		{
+			name:        "undeclared media type",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        "Rex",
+			header:      map[string]string{"X-Rate-Limit": "10"},
+			want:        []string{"/header/Content-Type"},
+		},
+		{
+			name:   "no content",
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "missing body",
+			status: http.StatusOK,
+			header: map[string]string{"X-Rate-Limit": "10"},
+			want:   []string{"/body"},
+		},
+		{
+			name:        "empty JSON body",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			header:      map[string]string{"X-Rate-Limit": "10"},
+			want:        []string{"/body"},
+		},
+		{
+			name:        "undeclared body",
+			status:      http.StatusNoContent,
+			contentType: "application/json",
+			body:        `{}`,
+			want:        []string{"/body"},
+		},
+		{
+			name:        "range",
+			status:      http.StatusNotFound,
+			contentType: "application/problem+json",
+			body:        `{"status": 404}`,
+		},
+		{
+			name:   "undeclared status",
+			status: http.StatusInternalServerError,
+			want:   []string{"/status"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				for key, value := range tt.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			recorder := &testingT{}
+			opts := openapi.ResponseValidation{T: recorder, Replace: true}
+			rec := httptest.NewRecorder()
+			openapi.ValidateResponses(doc, opts)(handler).ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+
+			if tt.want == nil {
+				if rec.Code != tt.status || rec.Body.String() != tt.body || len(recorder.errs) > 0 {
+					t.Errorf("unexpected response %d: %s, failures: %v", rec.Code, rec.Body, recorder.errs)
+				}
+				return
+			}
+			if len(recorder.errs) != 1 {
+				t.Errorf("expected one test failure, got %v", recorder.errs)
+			}
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("unexpected status code %d", rec.Code)
+			}
+			var problem openapi.Problem
+			err := json.NewDecoder(rec.Body).Decode(&problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range problem.Errors {
+				got = append(got, e.Pointer)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("expected errors at %q, got %+v", tt.want, problem)
+			}
+		})
+	}
+}
+
+func TestValidateResponses_Passthrough(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	recorder := &testingT{}
+	mw := openapi.ValidateResponses(petsAPI(), openapi.ResponseValidation{T: recorder})
+
+	// The invalid response is kept as is without Replace.
+	rec := httptest.NewRecorder()
+	mw(handler).ServeHTTP(rec, httptest.NewRequest("GET", "/pets/mine", nil))
+	if rec.Code != http.StatusTeapot || len(recorder.errs) != 1 {
+		t.Errorf("unexpected response %d, failures: %v", rec.Code, recorder.errs)
+	}
+
+	// Responses to unknown operations aren't checked.
+	rec = httptest.NewRecorder()
+	mw(handler).ServeHTTP(rec, httptest.NewRequest("GET", "/owners", nil))
+	if rec.Code != http.StatusTeapot || len(recorder.errs) != 1 {
+		t.Errorf("unexpected response %d, failures: %v", rec.Code, recorder.errs)
+	}
+}
@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrPathNotFound is returned by [Router.Match] if no path of the document matches the request.
+var ErrPathNotFound = errors.New("no path of the API matches the request")
+
+// MethodNotAllowedError is returned by [Router.Match] if the path matches
+// but has no operation for the request method.
+type MethodNotAllowedError struct {
+	Method string
+	// The methods of the operations of the matched path, in uppercase.
+	Allowed []string
+}
+
+func (e *MethodNotAllowedError) Error() string {
+	return fmt.Sprintf("method %s is not allowed, allowed methods are %s", e.Method, strings.Join(e.Allowed, ", "))
+}
+
+// Route is an operation matched to a request by [Router.Match].
+type Route struct {
+	// The path template, like "/pets/{id}".
+	Path     string
+	PathItem PathItem
+	// The request method in uppercase, like "GET".
+	Method    string
+	Operation Operation
+	// The unescaped values of the path parameters by their names.
+	PathParams map[string]string
+
+	// The escaped values of the path parameters, as [Parameter.Decode] expects them.
+	rawPathParams map[string]string
+}
+
+// Router matches HTTP requests to the operations of a document by their path templates.
+//
+// The request path must start with the base path of one of the servers of the operation,
+// like "/v1" for "https://api.example.com/v1". The hosts of the servers aren't checked.
+// Paths with concrete segments are matched before templated ones, so "/users/me" has
+// priority over "/users/{id}".
+type Router struct {
+	// Routes ordered by priority, the most specific first.
+	routes []route
+}
+
+// A path of the document for a set of servers, compiled for matching.
+type route struct {
+	path string
+	item PathItem
+	// Methods of the operations served by the servers, in uppercase.
+	methods []string
+	// Literal text and template expressions of each path segment.
+	segments []string
+	// The literal prefix of the base path, used to rank the routes.
+	base string
+	re   *regexp.Regexp
+	// Names of the path template expressions in the order of the regexp groups.
+	names []string
+}
+
+// NewRouter creates a [Router] for the paths of the document.
+//
+// The router uses a snapshot of the document, so it must not be changed afterwards.
+func NewRouter(doc *OpenAPI) *Router {
+	var routes []route
+	for path, item := range doc.Paths.Items {
+		servers := item.Servers
+		if len(servers) == 0 {
+			servers = doc.Servers
+		}
+		// Operations grouped by the base paths of their servers, in the order of appearance.
+		var groups [][]basePattern
+		methods := make(map[int][]string)
+		for method, op := range item.operations() {
+			if isZero(op) {
+				continue
+			}
+			bases := basePatterns(servers)
+			if len(op.Servers) > 0 {
+				bases = basePatterns(op.Servers)
+			}
+			i := slices.IndexFunc(groups, func(g []basePattern) bool { return slices.Equal(g, bases) })
+			if i < 0 {
+				i = len(groups)
+				groups = append(groups, bases)
+			}
+			methods[i] = append(methods[i], strings.ToUpper(method))
+		}
+		if len(groups) == 0 {
+			// The path item without operations still matches the path.
+			groups = append(groups, basePatterns(servers))
+		}
+		for i, bases := range groups {
+			for _, base := range bases {
+				routes = append(routes, newRoute(path, item, methods[i], base))
+			}
+		}
+	}
+	slices.SortFunc(routes, compareRoutes)
+	return &Router{routes: routes}
+}
+
+// basePattern is the base path of a server as a regexp and its literal prefix.
+type basePattern struct {
+	re      string
+	literal string
+}
+
+// basePatterns returns the base paths of the servers, or the root if there are none.
+func basePatterns(servers []Server) []basePattern {
+	if len(servers) == 0 {
+		return []basePattern{{}}
+	}
+	var patterns []basePattern
+	for _, server := range servers {
+		path := server.URL
+		if _, rest, ok := strings.Cut(path, "://"); ok {
+			// Absolute URL: drop the scheme and the host.
+			_, path, _ = strings.Cut(rest, "/")
+			path = "/" + path
+		}
+		path, _, _ = strings.Cut(path, "?")
+		path, _, _ = strings.Cut(path, "#")
+		path = "/" + strings.Trim(path, "/")
+		if path == "/" {
+			path = ""
+		}
+		var re strings.Builder
+		literal := path
+		last := 0
+		for i, m := range pathTemplateRe.FindAllStringSubmatchIndex(path, -1) {
+			if i == 0 {
+				literal = path[:m[0]]
+			}
+			re.WriteString(regexp.QuoteMeta(path[last:m[0]]))
+			re.WriteString(serverVariablePattern(server.Variables[path[m[2]:m[3]]]))
+			last = m[1]
+		}
+		re.WriteString(regexp.QuoteMeta(path[last:]))
+		pattern := basePattern{re: re.String(), literal: literal}
+		if !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// serverVariablePattern returns the regexp for the values of the server variable.
+func serverVariablePattern(v ServerVariable) string {
+	if len(v.Enum) == 0 {
+		return "[^/]*"
+	}
+	values := make([]string, len(v.Enum))
+	for i, value := range v.Enum {
+		values[i] = regexp.QuoteMeta(value)
+	}
+	return "(?:" + strings.Join(values, "|") + ")"
+}
+
+func newRoute(path string, item PathItem, methods []string, base basePattern) route {
+	var pattern strings.Builder
+	var names []string
+	pattern.WriteString("^")
+	pattern.WriteString(base.re)
+	last := 0
+	for _, m := range pathTemplateRe.FindAllStringSubmatchIndex(path, -1) {
+		pattern.WriteString(regexp.QuoteMeta(path[last:m[0]]))
+		pattern.WriteString("([^/]+)")
+		names = append(names, path[m[2]:m[3]])
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(path[last:]))
+	pattern.WriteString("$")
+	return route{
+		path:     path,
+		item:     item,
+		methods:  methods,
+		segments: strings.Split(path, "/"),
+		base:     base.literal,
+		re:       regexp.MustCompile(pattern.String()),
+		names:    names,
+	}
+}
+
+// compareRoutes orders the routes so that concrete path segments come before templated ones,
+// like "/users/me" before "/users/{id}", and longer base paths come first.
+func compareRoutes(a, b route) int {
+	for i := range min(len(a.segments), len(b.segments)) {
+		ta := strings.Contains(a.segments[i], "{")
+		tb := strings.Contains(b.segments[i], "{")
+		if ta != tb {
+			return boolToInt(ta) - boolToInt(tb)
+		}
+	}
+	if len(a.base) != len(b.base) {
+		return len(b.base) - len(a.base)
+	}
+	return strings.Compare(a.path, b.path)
+}
+
+// Match finds the operation for the request.
+//
+// If no path matches, the error is [ErrPathNotFound]. If the path matches
+// but there is no operation for the method, the error is [*MethodNotAllowedError].
+func (r *Router) Match(req *http.Request) (Route, error) {
+	path := req.URL.EscapedPath()
+	var matched string
+	var allowed []string
+	for i := range r.routes {
+		rt := &r.routes[i]
+		if matched != "" && rt.path != matched {
+			continue
+		}
+		m := rt.re.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		// Only the routes of the most specific matching path are considered.
+		matched = rt.path
+		if !slices.Contains(rt.methods, req.Method) {
+			allowed = append(allowed, rt.methods...)
+			continue
+		}
+		op, _ := rt.item.operation(req.Method)
+		params := make(map[string]string, len(rt.names))
+		rawParams := make(map[string]string, len(rt.names))
+		for j, name := range rt.names {
+			value, err := url.PathUnescape(m[j+1])
+			if err != nil {
+				value = m[j+1]
+			}
+			params[name] = value
+			rawParams[name] = m[j+1]
+		}
+		return Route{
+			Path:       rt.path,
+			PathItem:   rt.item,
+			Method:     req.Method,
+			Operation:  op,
+			PathParams: params,
+
+			rawPathParams: rawParams,
+		}, nil
+	}
+	if matched == "" {
+		return Route{}, ErrPathNotFound
+	}
+	slices.Sort(allowed)
+	return Route{}, &MethodNotAllowedError{Method: req.Method, Allowed: slices.Compact(allowed)}
+}
+
+// operation returns the operation for the HTTP method, if the path item defines it.
+func (p PathItem) operation(method string) (Operation, bool) {
+	method = strings.ToLower(method)
+	for name, op := range p.operations() {
+		if name == method {
+			return op, !isZero(op)
+		}
+	}
+	return Operation{}, false
+}
@@ -0,0 +1,100 @@
+package openapi_test
+
+import (
+	"errors"
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestRouter(t *testing.T) {
+	ok := openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "OK"})}
+	doc := &openapi.OpenAPI{
+		Version: "3.1.0",
+		Servers: []openapi.Server{
+			{URL: "https://api.example.com/v1/"},
+			{
+				URL: "https://{region}.example.com/{version}",
+				Variables: map[string]openapi.ServerVariable{
+					"region":  {Default: "eu"},
+					"version": {Default: "v2", Enum: []string{"v2", "v3"}},
+				},
+			},
+		},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/users/{id}": {
+				Get:    openapi.Operation{OperationID: "getUser", Responses: ok},
+				Delete: openapi.Operation{OperationID: "deleteUser", Responses: ok},
+			},
+			"/users/me": {
+				Get: openapi.Operation{OperationID: "getMe", Responses: ok},
+			},
+			"/users/{id}/files/{name}.{ext}": {
+				Get: openapi.Operation{OperationID: "getFile", Responses: ok},
+			},
+			"/health": {
+				Servers: []openapi.Server{{URL: "/"}},
+				Get:     openapi.Operation{OperationID: "health", Responses: ok},
+				Post: openapi.Operation{
+					OperationID: "setHealth",
+					Servers:     []openapi.Server{{URL: "/admin"}},
+					Responses:   ok,
+				},
+			},
+		}},
+	}
+	router := openapi.NewRouter(doc)
+	tests := []struct {
+		method string
+		target string
+		// The expected operation ID and path parameters.
+		want   string
+		params map[string]string
+	}{
+		{"GET", "/v1/users/me", "getMe", map[string]string{}},
+		{"GET", "/v1/users/42", "getUser", map[string]string{"id": "42"}},
+		{"DELETE", "/v3/users/a%2Fb", "deleteUser", map[string]string{"id": "a/b"}},
+		{"GET", "/v1/users/42/files/report.pdf", "getFile", map[string]string{"id": "42", "name": "report", "ext": "pdf"}},
+		{"GET", "/health", "health", map[string]string{}},
+		{"POST", "/admin/health", "setHealth", map[string]string{}},
+	}
+	for _, tt := range tests {
+		route, err := router.Match(httptest.NewRequest(tt.method, tt.target, nil))
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.method, tt.target, err)
+			continue
+		}
+		if route.Operation.OperationID != tt.want || route.Method != tt.method || !maps.Equal(route.PathParams, tt.params) {
+			t.Errorf("%s %s: unexpected route %s %s %v", tt.method, tt.target, route.Operation.OperationID, route.Path, route.PathParams)
+		}
+	}
+
+	for _, target := range []string{"/users/42", "/v4/users/42", "/v1/users", "/v1/users/42/", "/v1/health", "/admin/users/me"} {
+		_, err := router.Match(httptest.NewRequest("GET", target, nil))
+		if !errors.Is(err, openapi.ErrPathNotFound) {
+			t.Errorf("GET %s: expected ErrPathNotFound, got %v", target, err)
+		}
+	}
+
+	methodTests := []struct {
+		method  string
+		target  string
+		allowed []string
+	}{
+		{"POST", "/v1/users/42", []string{"DELETE", "GET"}},
+		// The concrete path has priority even if only the templated one has the method.
+		{"DELETE", "/v1/users/me", []string{"GET"}},
+		{"POST", "/health", []string{"GET"}},
+		{"GET", "/admin/health", []string{"POST"}},
+	}
+	for _, tt := range methodTests {
+		_, err := router.Match(httptest.NewRequest(tt.method, tt.target, nil))
+		var notAllowed *openapi.MethodNotAllowedError
+		if !errors.As(err, &notAllowed) || !slices.Equal(notAllowed.Allowed, tt.allowed) {
+			t.Errorf("%s %s: expected methods %v, got %v", tt.method, tt.target, tt.allowed, err)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
+)
+
+// Names of the primitive types supported by JSON Schema.
+const (
+	TypeNull    = "null"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeNumber  = "number"
+	TypeString  = "string"
+	TypeInteger = "integer"
+)
+
+// The Schema Object allows the definition of input and output data types. These types can be objects, but also primitives and arrays. This object is a superset of the JSON Schema Specification Draft 2020-12.
+type Schema struct {
+	// If not nil, the schema is a boolean schema: true accepts any instance and false rejects every instance. All other fields are ignored.
+	Boolean *bool `json:"-"`
+
+	// The URI of the dialect (meta-schema) the schema is written in.
+	Schema string `json:"$schema,omitzero"`
+	// The canonical URI of the schema resource.
+	ID string `json:"$id,omitzero"`
+	// A reference to another schema which is applied to the same instance.
+	Ref string `json:"$ref,omitzero"`
+	// A plain name fragment that can be used to reference the schema.
+	Anchor string `json:"$anchor,omitzero"`
+	// A reference that is resolved dynamically, starting from the outermost schema resource in the evaluation path.
+	DynamicRef string `json:"$dynamicRef,omitzero"`
+	// A plain name fragment that can be used as a target of $dynamicRef.
+	DynamicAnchor string `json:"$dynamicAnchor,omitzero"`
+	// Re-usable schemas that can be referenced from within the schema resource.
+	Defs map[string]*Schema `json:"$defs,omitzero"`
+	// Comments from the schema author. It has no effect on validation.
+	Comment string `json:"$comment,omitzero"`
+
+	// The instance is valid if it is valid against all of the listed schemas.
+	AllOf []*Schema `json:"allOf,omitzero"`
+	// The instance is valid if it is valid against at least one of the listed schemas.
+	AnyOf []*Schema `json:"anyOf,omitzero"`
+	// The instance is valid if it is valid against exactly one of the listed schemas.
+	OneOf []*Schema `json:"oneOf,omitzero"`
+	// The instance is valid if it is not valid against the schema.
+	Not *Schema `json:"not,omitzero"`
+	// If the instance is valid against this schema, it must also be valid against Then, otherwise against Else.
+	If *Schema `json:"if,omitzero"`
+	// The schema applied if the instance is valid against If.
+	Then *Schema `json:"then,omitzero"`
+	// The schema applied if the instance is not valid against If.
+	Else *Schema `json:"else,omitzero"`
+	// Schemas applied to the whole object if the property with the same name is present.
+	DependentSchemas map[string]*Schema `json:"dependentSchemas,omitzero"`
+
+	// Schemas for the array items at the corresponding positions.
+	PrefixItems []*Schema `json:"prefixItems,omitzero"`
+	// The schema for all array items not covered by PrefixItems.
+	Items *Schema `json:"items,omitzero"`
+	// The array must contain at least one item valid against the schema.
+	Contains *Schema `json:"contains,omitzero"`
+	// Schemas for the object properties with the same names.
+	Properties map[string]*Schema `json:"properties,omitzero"`
+	// Schemas for the object properties with names matching the regular expression used as the key.
+	PatternProperties map[string]*Schema `json:"patternProperties,omitzero"`
+	// The schema for all object properties not covered by Properties and PatternProperties.
+	AdditionalProperties *Schema `json:"additionalProperties,omitzero"`
+	// The schema that all object property names must be valid against.
+	PropertyNames *Schema `json:"propertyNames,omitzero"`
+	// The schema for array items that weren't evaluated by any other keyword.
+	UnevaluatedItems *Schema `json:"unevaluatedItems,omitzero"`
+	// The schema for object properties that weren't evaluated by any other keyword.
+	UnevaluatedProperties *Schema `json:"unevaluatedProperties,omitzero"`
+
+	// The list of allowed JSON types.
+	Type Types `json:"type,omitzero"`
+	// OpenAPI 3.0 only. Allows the null value in addition to the listed types.
+	// In OpenAPI 3.1, add [TypeNull] to Type instead.
+	Nullable bool `json:"nullable,omitzero"`
+	// The instance must be equal to one of the listed values.
+	Enum []any `json:"enum,omitzero"`
+	// The instance must be equal to the value. Use [Null] for null.
+	Const any `json:"const,omitzero"`
+
+	// A numeric instance must be a multiple of the value. The value MUST be strictly greater than 0.
+	MultipleOf json.Number `json:"multipleOf,omitzero"`
+	// A numeric instance must be less than or equal to the value.
+	Maximum json.Number `json:"maximum,omitzero"`
+	// A numeric instance must be strictly less than the value.
+	ExclusiveMaximum json.Number `json:"exclusiveMaximum,omitzero"`
+	// OpenAPI 3.0 only. Makes Maximum exclusive. Encoded as the boolean form of exclusiveMaximum.
+	MaximumExclusive bool `json:"-"`
+	// A numeric instance must be greater than or equal to the value.
+	Minimum json.Number `json:"minimum,omitzero"`
+	// A numeric instance must be strictly greater than the value.
+	ExclusiveMinimum json.Number `json:"exclusiveMinimum,omitzero"`
+	// OpenAPI 3.0 only. Makes Minimum exclusive. Encoded as the boolean form of exclusiveMinimum.
+	MinimumExclusive bool `json:"-"`
+
+	// A string instance must be no longer than the value, in characters.
+	MaxLength *int `json:"maxLength,omitzero"`
+	// A string instance must be at least as long as the value, in characters.
+	MinLength int `json:"minLength,omitzero"`
+	// A string instance must match the ECMA-262 regular expression.
+	Pattern string `json:"pattern,omitzero"`
+
+	// An array instance must have no more items than the value.
+	MaxItems *int `json:"maxItems,omitzero"`
+	// An array instance must have at least as many items as the value.
+	MinItems int `json:"minItems,omitzero"`
+	// If true, all items of an array instance must be unique.
+	UniqueItems bool `json:"uniqueItems,omitzero"`
+	// An array instance must have no more items valid against Contains than the value.
+	MaxContains *int `json:"maxContains,omitzero"`
+	// An array instance must have at least as many items valid against Contains as the value. Default value is 1.
+	MinContains *int `json:"minContains,omitzero"`
+
+	// An object instance must have no more properties than the value.
+	MaxProperties *int `json:"maxProperties,omitzero"`
+	// An object instance must have at least as many properties as the value.
+	MinProperties int `json:"minProperties,omitzero"`
+	// Names of the properties that an object instance must have.
+	Required []string `json:"required,omitzero"`
+	// If an object instance has the property with the name used as the key, it must also have all the listed properties.
+	DependentRequired map[string][]string `json:"dependentRequired,omitzero"`
+
+	// The semantic format of the instance, like "date-time", "email", or "int64".
+	Format string `json:"format,omitzero"`
+	// The encoding used to store binary data in a string instance, like "base64".
+	ContentEncoding string `json:"contentEncoding,omitzero"`
+	// The media type of the content of a string instance.
+	ContentMediaType string `json:"contentMediaType,omitzero"`
+	// The schema for the decoded content of a string instance.
+	ContentSchema *Schema `json:"contentSchema,omitzero"`
+
+	// A short title for the schema.
+	Title string `json:"title,omitzero"`
+	// A description of the schema. CommonMark syntax MAY be used for rich text representation.
+	Description string `json:"description,omitzero"`
+	// The default value for the instance. Use [Null] for null.
+	Default any `json:"default,omitzero"`
+	// Specifies that the instance is deprecated and SHOULD be transitioned out of usage.
+	Deprecated bool `json:"deprecated,omitzero"`
+	// The instance is managed by the server and SHOULD NOT be sent in requests.
+	ReadOnly bool `json:"readOnly,omitzero"`
+	// The instance is never returned by the server and SHOULD NOT be sent in responses.
+	WriteOnly bool `json:"writeOnly,omitzero"`
+	// Example values of the instance.
+	Examples []any `json:"examples,omitzero"`
+
+	// Adds support for polymorphism. The discriminator is used to determine which of a set of schemas a payload is expected to satisfy.
+	Discriminator Discriminator `json:"discriminator,omitzero"`
+	// This MAY be used only on property schemas. It has no effect on root schemas. Adds additional metadata to describe the XML representation of this property.
+	XML XML `json:"xml,omitzero"`
+	// Additional external documentation for this schema.
+	ExternalDocs ExternalDoc `json:"externalDocs,omitzero"`
+	// A free-form field to include an example of an instance for this schema. Deprecated in favor of Examples. Use [Null] for null.
+	Example any `json:"example,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
+	// Keywords not covered by the fields above, except extensions. They are encoded alongside the known keywords.
+	Extra map[string]any `json:"-"`
+}
+
+// Null is the JSON null value for the keywords of [Schema] that hold any value, like Default,
+// since nil means that the keyword is absent. Decoding a schema sets such keywords that are null to Null.
+type Null struct{}
+
+func (Null) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// BoolSchema returns a boolean schema. The true schema accepts any instance and the false schema rejects every instance.
+func BoolSchema(b bool) *Schema {
+	return &Schema{Boolean: &b}
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Boolean != nil {
+		return json.Marshal(*s.Boolean)
+	}
+	err := checkExtensions(s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	extra := s.Extra
+	if len(s.Extensions) > 0 || s.MaximumExclusive || s.MinimumExclusive {
+		extra = make(map[string]any, len(s.Extensions)+len(s.Extra)+2)
+		maps.Copy(extra, s.Extensions)
+		maps.Copy(extra, s.Extra)
+	}
+	if s.MaximumExclusive {
+		if s.ExclusiveMaximum != "" {
+			return nil, errors.New("schema can't have both numeric ExclusiveMaximum and MaximumExclusive")
+		}
+		extra["exclusiveMaximum"] = true
+	}
+	if s.MinimumExclusive {
+		if s.ExclusiveMinimum != "" {
+			return nil, errors.New("schema can't have both numeric ExclusiveMinimum and MinimumExclusive")
+		}
+		extra["exclusiveMinimum"] = true
+	}
+	type schema Schema
+	return marshalInline(schema(s), extra)
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("true")) || bytes.Equal(data, []byte("false")) {
+		b := data[0] == 't'
+		*s = Schema{Boolean: &b}
+		return nil
+	}
+	*s = Schema{}
+	data, err := s.unmarshalExclusiveFlags(data)
+	if err != nil {
+		return err
+	}
+	type schema Schema
+	unknown, err := unmarshalInline(data, (*schema)(s))
+	if err != nil {
+		return err
+	}
+	err = s.unmarshalNulls(data)
+	if err != nil {
+		return err
+	}
+	for key, raw := range unknown {
+		var val any
+		err = decodeJSON(raw, &val)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(key, "x-") {
+			if s.Extensions == nil {
+				s.Extensions = make(map[string]any)
+			}
+			s.Extensions[key] = val
+			continue
+		}
+		if s.Extra == nil {
+			s.Extra = make(map[string]any)
+		}
+		s.Extra[key] = val
+	}
+	return nil
+}
+
+// The list of JSON types allowed by a [Schema]. It's encoded as a single string if it has only one element.
+type Types []string
+
+// unmarshalExclusiveFlags sets MaximumExclusive and MinimumExclusive
+// from the boolean form of exclusiveMaximum and exclusiveMinimum used by OpenAPI 3.0
+// and returns the object without them.
+func (s *Schema) unmarshalExclusiveFlags(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("exclusiveM")) {
+		return data, nil
+	}
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for key, flag := range map[string]*bool{
+		"exclusiveMaximum": &s.MaximumExclusive,
+		"exclusiveMinimum": &s.MinimumExclusive,
+	} {
+		raw := bytes.TrimSpace(fields[key])
+		if bytes.Equal(raw, []byte("true")) || bytes.Equal(raw, []byte("false")) {
+			*flag = raw[0] == 't'
+			delete(fields, key)
+			found = true
+		}
+	}
+	if !found {
+		return data, nil
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalNulls sets the keywords holding any value to [Null] if they are null in the JSON object.
+func (s *Schema) unmarshalNulls(data []byte) error {
+	if !bytes.Contains(data, []byte("null")) {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	for key, field := range map[string]*any{
+		"const":   &s.Const,
+		"default": &s.Default,
+		"example": &s.Example,
+	} {
+		if bytes.Equal(bytes.TrimSpace(fields[key]), []byte("null")) {
+			*field = Null{}
+		}
+	}
+	return nil
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var val any
+	if json.Unmarshal(data, &val) == nil {
+		if single, ok := val.(string); ok {
+			*t = Types{single}
+			return nil
+		}
+		if list, ok := val.([]any); ok {
+			types := make(Types, len(list))
+			for i, item := range list {
+				types[i], ok = item.(string)
+				if !ok {
+					break
+				}
+			}
+			if ok {
+				*t = types
+				return nil
+			}
+		}
+	}
+	return errors.New("schema type must be a string or an array of strings")
+}
+
+// When request bodies or response payloads may be one of a number of different schemas, a Discriminator Object gives a hint about the expected schema of the document.
+type Discriminator struct {
+	// REQUIRED. The name of the property in the payload that will hold the discriminating value. This property SHOULD be required in the payload schema.
+	PropertyName string `json:"propertyName"`
+	// An object to hold mappings between payload values and schema names or URI references.
+	Mapping map[string]string `json:"mapping,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
+}
+
+// A metadata object that allows for more fine-tuned XML model definitions.
+type XML struct {
+	// Replaces the name of the element/attribute used for the described schema property.
+	Name string `json:"name,omitzero"`
+	// The URI of the namespace definition. Value MUST be in the form of a non-relative URI.
+	Namespace string `json:"namespace,omitzero"`
+	// The prefix to be used for the name.
+	Prefix string `json:"prefix,omitzero"`
+	// Declares whether the property definition translates to an attribute instead of an element. Default value is false.
+	Attribute bool `json:"attribute,omitzero"`
+	// MAY be used only for an array definition. Signifies whether the array is wrapped (for example, <books><book/><book/></books>) or unwrapped (<book/><book/>). Default value is false.
+	Wrapped bool `json:"wrapped,omitzero"`
+
+	// Specification extensions. The keys MUST begin with "x-".
+	Extensions map[string]any `json:"-"`
+}
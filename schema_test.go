@@ -0,0 +1,157 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	cases := []struct {
+		schema *openapi.Schema
+		want   string
+	}{
+		{openapi.BoolSchema(true), `true`},
+		{openapi.BoolSchema(false), `false`},
+		{&openapi.Schema{}, `{}`},
+		{&openapi.Schema{Type: openapi.Types{openapi.TypeString}}, `{"type":"string"}`},
+		{&openapi.Schema{Type: openapi.Types{openapi.TypeString, openapi.TypeNull}}, `{"type":["string","null"]}`},
+		{&openapi.Schema{Minimum: "0", MaxItems: ptr(0)}, `{"maxItems":0,"minimum":0}`},
+		{
+			&openapi.Schema{
+				Type:  openapi.Types{openapi.TypeArray},
+				Items: &openapi.Schema{Ref: "#/components/schemas/User"},
+			},
+			`{"items":{"$ref":"#/components/schemas/User"},"type":"array"}`,
+		},
+		{
+			&openapi.Schema{
+				Properties: map[string]*openapi.Schema{"id": {Type: openapi.Types{openapi.TypeInteger}}},
+				Extra:      map[string]any{"$vocabulary": map[string]bool{}},
+			},
+			`{"properties":{"id":{"type":"integer"}},"$vocabulary":{}}`,
+		},
+	}
+	for _, c := range cases {
+		got, err := json.Marshal(c.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compare as decoded values since the field order doesn't matter.
+		if !jsonEqual(t, got, []byte(c.want)) {
+			t.Errorf("got %s, want %s", got, c.want)
+		}
+	}
+}
+
+func TestSchema_UnmarshalJSON(t *testing.T) {
+	input := `{
+		"type": ["object", "null"],
+		"properties": {"name": {"type": "string", "minLength": 1}},
+		"additionalProperties": false,
+		"required": ["name"],
+		"unknownKeyword": 13
+	}`
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(input), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Type) != 2 || s.Type[1] != openapi.TypeNull {
+		t.Errorf("unexpected type: %v", s.Type)
+	}
+	if s.Properties["name"].MinLength != 1 {
+		t.Errorf("unexpected properties: %v", s.Properties)
+	}
+	if s.AdditionalProperties.Boolean == nil || *s.AdditionalProperties.Boolean {
+		t.Errorf("additionalProperties must be false schema")
+	}
+	if s.Extra["unknownKeyword"] != json.Number("13") {
+		t.Errorf("unexpected extra: %v", s.Extra)
+	}
+	output, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, output, []byte(input)) {
+		t.Errorf("round trip changed the schema: %s", output)
+	}
+}
+
+func TestSchema_RoundTrip(t *testing.T) {
+	input := `{"const":null,"maximum":9007199254740993,"minimum":0.10,"default":null,"example":null}`
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(input), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Default != (openapi.Null{}) {
+		t.Errorf("unexpected default: %#v", s.Default)
+	}
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != input {
+		t.Errorf("got %s, want %s", got, input)
+	}
+}
+
+func TestSchema_UnmarshalJSON_InvalidType(t *testing.T) {
+	for _, input := range []string{`{"type": null}`, `{"type": ["string", null]}`, `{"type": 1}`} {
+		var s openapi.Schema
+		err := json.Unmarshal([]byte(input), &s)
+		if err == nil {
+			t.Errorf("expected an error for %s", input)
+		}
+	}
+}
+
+func TestSchema_OAS30(t *testing.T) {
+	input := `{"type": "integer", "nullable": true, "minimum": 0, "exclusiveMinimum": true, "exclusiveMaximum": 10}`
+	var s openapi.Schema
+	err := json.Unmarshal([]byte(input), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Nullable || !s.MinimumExclusive || s.MaximumExclusive {
+		t.Errorf("unexpected flags: nullable=%v minimumExclusive=%v maximumExclusive=%v", s.Nullable, s.MinimumExclusive, s.MaximumExclusive)
+	}
+	if s.ExclusiveMinimum != "" || s.ExclusiveMaximum != "10" {
+		t.Errorf("unexpected exclusive bounds: %v, %v", s.ExclusiveMinimum, s.ExclusiveMaximum)
+	}
+	if len(s.Extra) != 0 {
+		t.Errorf("unexpected extra: %v", s.Extra)
+	}
+	output, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, output, []byte(input)) {
+		t.Errorf("round trip changed the schema: %s", output)
+	}
+
+	_, err = json.Marshal(openapi.Schema{Maximum: "1", MaximumExclusive: true, ExclusiveMaximum: "1"})
+	if err == nil {
+		t.Error("expected an error for both forms of exclusiveMaximum")
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatal(err)
+	}
+	ra, _ := json.Marshal(va)
+	rb, _ := json.Marshal(vb)
+	return string(ra) == string(rb)
+}
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// SchemaValidator checks JSON values against the schemas of a document.
+//
+// References in the schemas are resolved against the document,
+// so that "#/components/schemas/Pet" points to the Pet schema in Components.
+// Without a document, they are resolved against the validated schema itself,
+// like "#/$defs/Pet" in the schemas produced by [SchemaFor].
+//
+// The validator uses a snapshot of the document and of each schema at its first use,
+// so they must not be changed afterwards. Likewise, the fields must be set before the first use.
+// Up to 10000 schemas are compiled once, other ones are compiled on each use.
+// It's safe for concurrent use.
+type SchemaValidator struct {
+	// Checkers for the format keyword. Default value is [DefaultFormats].
+	// Formats without a checker are always valid.
+	Formats Formats
+	// If true, the values must match the format of their schema.
+	// Otherwise, format is only an annotation, as JSON Schema 2020-12 prescribes by default.
+	AssertFormat bool
+
+	evaluator *schemaEvaluator
+	// If there is no document, references are resolved against the schema itself.
+	detached bool
+
+	mu sync.Mutex
+	// Compiled schemas by their pointers.
+	schemas map[*Schema]compiledSchema
+}
+
+// The number of compiled schemas that [SchemaValidator] keeps. It's enough for the schemas
+// of most documents and keeps the memory bounded when schemas are created for each validation.
+const maxCachedSchemas = 10_000
+
+// A schema converted into a generic JSON value, ready for evaluation.
+type compiledSchema struct {
+	root      any
+	evaluator *schemaEvaluator
+}
+
+// NewSchemaValidator creates a validator for the schemas of the document.
+//
+// The schemas are evaluated by the rules of the dialect of the document,
+// see [OpenAPI.SchemaDialect]. The document may be nil for standalone schemas,
+// which are evaluated as JSON Schema 2020-12.
+func NewSchemaValidator(doc *OpenAPI) (*SchemaValidator, error) {
+	var raw any
+	d := dialectDraft2020
+	if doc != nil {
+		uri := doc.SchemaDialect()
+		var ok bool
+		d, ok = dialectOf(uri)
+		if !ok {
+			return nil, fmt.Errorf("unsupported JSON Schema dialect %q", uri)
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		err = decodeJSON(data, &raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	index := newSchemaIndex(d)
+	index.add("", raw)
+	return &SchemaValidator{
+		Formats:   DefaultFormats(),
+		evaluator: &schemaEvaluator{index: index},
+		detached:  doc == nil,
+		schemas:   make(map[*Schema]compiledSchema),
+	}, nil
+}
+
+// Validate checks the value against the schema.
+//
+// The value is a decoded JSON value, like the result of [json.Unmarshal] into any.
+// Other values are encoded into JSON first, so that a struct is checked as a JSON object.
+// If the value is invalid, the returned error is [SchemaErrors].
+func (v *SchemaValidator) Validate(s *Schema, value any) error {
+	compiled, err := v.compile(s)
+	if err != nil {
+		return err
+	}
+	value, err = normalizeJSON(value)
+	if err != nil {
+		return err
+	}
+	evaluator := *compiled.evaluator
+	if v.AssertFormat {
+		evaluator.formats = v.Formats
+	}
+	errs := evaluator.validate("", compiled.root, value)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v *SchemaValidator) compile(s *Schema) (compiledSchema, error) {
+	if s == nil {
+		return compiledSchema{root: true, evaluator: v.evaluator}, nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if compiled, ok := v.schemas[s]; ok {
+		return compiled, nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return compiledSchema{}, err
+	}
+	var root any
+	err = decodeJSON(data, &root)
+	if err != nil {
+		return compiledSchema{}, err
+	}
+	compiled := compiledSchema{root: root, evaluator: v.evaluator}
+	if v.detached || hasIdentifiers(root) {
+		// The schema is the base document or declares its own resources or anchors,
+		// which are added to a copy of the index to keep the shared one immutable.
+		index := v.evaluator.index.clone()
+		if v.detached {
+			index.add("", root)
+		} else {
+			index.walk("", root)
+		}
+		compiled.evaluator = &schemaEvaluator{index: index}
+	}
+	if len(v.schemas) < maxCachedSchemas {
+		v.schemas[s] = compiled
+	}
+	return compiled, nil
+}
+
+// hasIdentifiers reports if the generic JSON value has schemas with $id, $anchor, or $dynamicAnchor.
+func hasIdentifiers(node any) bool {
+	switch node := node.(type) {
+	case map[string]any:
+		for key, child := range node {
+			if key == "$id" || key == "$anchor" || key == "$dynamicAnchor" {
+				return true
+			}
+			if hasIdentifiers(child) {
+				return true
+			}
+		}
+	case []any:
+		for _, child := range node {
+			if hasIdentifiers(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeJSON converts the value into a generic JSON value if it isn't one already.
+func normalizeJSON(v any) (any, error) {
+	if isGenericJSON(v) {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	err = decodeJSON(data, &generic)
+	return generic, err
+}
+
+func isGenericJSON(v any) bool {
+	switch v := v.(type) {
+	case nil, bool, string, json.Number, float64:
+		return true
+	case []any:
+		for _, item := range v {
+			if !isGenericJSON(item) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		for _, item := range v {
+			if !isGenericJSON(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,385 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestSchemaValidator(t *testing.T) {
+	doc := &openapi.OpenAPI{
+		Version: "3.1.0",
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Pet": {
+					Type:     openapi.Types{openapi.TypeObject},
+					Required: []string{"name"},
+					Properties: map[string]*openapi.Schema{
+						"name": {Type: openapi.Types{openapi.TypeString}, MinLength: 1},
+						"tags": {Type: openapi.Types{openapi.TypeArray}, Items: &openapi.Schema{Type: openapi.Types{openapi.TypeString}}},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		schema *openapi.Schema
+		value  string
+		// Keyword locations of the expected errors.
+		want []string
+	}{
+		{
+			name:   "type",
+			schema: &openapi.Schema{Type: openapi.Types{openapi.TypeInteger, openapi.TypeNull}},
+			value:  `1.5`,
+			want:   []string{"/type"},
+		},
+		{
+			name:   "integer with a zero fraction",
+			schema: &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}},
+			value:  `2.0`,
+		},
+		{
+			name:   "enum",
+			schema: &openapi.Schema{Enum: []any{"cat", "dog", json.Number("1")}},
+			value:  `1.0`,
+		},
+		{
+			name:   "const",
+			schema: &openapi.Schema{Const: map[string]any{"a": []any{true}}},
+			value:  `{"a": [false]}`,
+			want:   []string{"/const"},
+		},
+		{
+			name: "numbers",
+			schema: &openapi.Schema{
+				Minimum:          "1",
+				ExclusiveMaximum: "10",
+				MultipleOf:       "0.1",
+			},
+			value: `10`,
+			want:  []string{"/exclusiveMaximum"},
+		},
+		{
+			name:   "multipleOf",
+			schema: &openapi.Schema{MultipleOf: "0.1"},
+			value:  `0.35`,
+			want:   []string{"/multipleOf"},
+		},
+		{
+			name:   "strings",
+			schema: &openapi.Schema{MaxLength: ptr(3), Pattern: "^[a-z]+$"},
+			value:  `"héllo"`,
+			want:   []string{"/maxLength", "/pattern"},
+		},
+		{
+			name:   "string length in characters",
+			schema: &openapi.Schema{MaxLength: ptr(2)},
+			value:  `"éé"`,
+		},
+		{
+			name: "tuples",
+			schema: &openapi.Schema{
+				PrefixItems: []*openapi.Schema{{Type: openapi.Types{openapi.TypeString}}},
+				Items:       &openapi.Schema{Type: openapi.Types{openapi.TypeInteger}},
+				MinItems:    2,
+				UniqueItems: true,
+			},
+			value: `["a", 1, "b", 1]`,
+			want:  []string{"/uniqueItems", "/items/type"},
+		},
+		{
+			name:   "contains",
+			schema: &openapi.Schema{Contains: &openapi.Schema{Const: "x"}, MaxContains: ptr(1)},
+			value:  `["x", "y", "x"]`,
+			want:   []string{"/maxContains"},
+		},
+		{
+			name: "objects",
+			schema: &openapi.Schema{
+				Properties:           map[string]*openapi.Schema{"id": {Type: openapi.Types{openapi.TypeInteger}}},
+				PatternProperties:    map[string]*openapi.Schema{"^x-": {Type: openapi.Types{openapi.TypeString}}},
+				AdditionalProperties: openapi.BoolSchema(false),
+				Required:             []string{"id", "name"},
+				DependentRequired:    map[string][]string{"x-a": {"x-b"}},
+			},
+			value: `{"id": "1", "x-a": "a", "extra": true}`,
+			want:  []string{"/required", "/dependentRequired/x-a", "/additionalProperties", "/properties/id/type"},
+		},
+		{
+			name:   "propertyNames",
+			schema: &openapi.Schema{PropertyNames: &openapi.Schema{Pattern: "^[a-z]+$"}},
+			value:  `{"ok": 1, "Bad": 2}`,
+			want:   []string{"/propertyNames/pattern"},
+		},
+		{
+			name: "combinators",
+			schema: &openapi.Schema{
+				AllOf: []*openapi.Schema{{Type: openapi.Types{openapi.TypeNumber}}},
+				AnyOf: []*openapi.Schema{{Minimum: "10"}, {Maximum: "0"}},
+				OneOf: []*openapi.Schema{{MultipleOf: "2"}, {MultipleOf: "3"}},
+				Not:   &openapi.Schema{Const: json.Number("12")},
+			},
+			value: `6`,
+			want:  []string{"/anyOf", "/anyOf/0/minimum", "/anyOf/1/maximum", "/oneOf"},
+		},
+		{
+			name: "conditionals",
+			schema: &openapi.Schema{
+				If:   &openapi.Schema{Properties: map[string]*openapi.Schema{"kind": {Const: "cat"}}},
+				Then: &openapi.Schema{Required: []string{"meows"}},
+				Else: &openapi.Schema{Required: []string{"barks"}},
+				DependentSchemas: map[string]*openapi.Schema{
+					"meows": {Properties: map[string]*openapi.Schema{"meows": {Type: openapi.Types{openapi.TypeBoolean}}}},
+				},
+			},
+			value: `{"kind": "cat", "meows": 1}`,
+			want:  []string{"/dependentSchemas/meows/properties/meows/type"},
+		},
+		{
+			name: "unevaluatedProperties",
+			schema: &openapi.Schema{
+				AllOf:                 []*openapi.Schema{{Properties: map[string]*openapi.Schema{"a": {}}}},
+				AnyOf:                 []*openapi.Schema{{Properties: map[string]*openapi.Schema{"b": {}}}, {Required: []string{"c"}}},
+				UnevaluatedProperties: openapi.BoolSchema(false),
+			},
+			value: `{"a": 1, "b": 2, "c": 3}`,
+			want:  []string{"/unevaluatedProperties"},
+		},
+		{
+			name: "unevaluatedItems",
+			schema: &openapi.Schema{
+				PrefixItems:      []*openapi.Schema{{}},
+				Contains:         &openapi.Schema{Type: openapi.Types{openapi.TypeString}},
+				UnevaluatedItems: &openapi.Schema{Type: openapi.Types{openapi.TypeBoolean}},
+			},
+			value: `[1, "a", 2]`,
+			want:  []string{"/unevaluatedItems/type"},
+		},
+		{
+			name:   "ref",
+			schema: &openapi.Schema{Type: openapi.Types{openapi.TypeArray}, Items: &openapi.Schema{Ref: "#/components/schemas/Pet"}},
+			value:  `[{"name": "Rex", "tags": ["good"]}, {"name": "", "tags": [1]}]`,
+			want:   []string{"/items/$ref/properties/name/minLength", "/items/$ref/properties/tags/items/type"},
+		},
+		{
+			name:   "unresolved ref",
+			schema: &openapi.Schema{Ref: "#/components/schemas/Missing"},
+			value:  `{}`,
+			want:   []string{"/$ref"},
+		},
+		{
+			name:   "boolean schema",
+			schema: openapi.BoolSchema(false),
+			value:  `null`,
+			want:   []string{""},
+		},
+	}
+	v, err := openapi.NewSchemaValidator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := json.NewDecoder(strings.NewReader(tt.value))
+			dec.UseNumber()
+			var value any
+			err := dec.Decode(&value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = v.Validate(tt.schema, value)
+			var got []string
+			var errs openapi.SchemaErrors
+			if errors.As(err, &errs) {
+				for _, e := range errs {
+					got = append(got, e.KeywordLocation)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected errors at %q, got:\n%v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_InstanceLocation(t *testing.T) {
+	schema := &openapi.Schema{
+		Properties: map[string]*openapi.Schema{
+			"pets": {Items: &openapi.Schema{Required: []string{"name"}}},
+		},
+	}
+	v, err := openapi.NewSchemaValidator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.Validate(schema, map[string]any{"pets": []any{map[string]any{"name": "Rex"}, map[string]any{}}})
+	want := openapi.SchemaErrors{{
+		InstanceLocation: "/pets/1",
+		KeywordLocation:  "/properties/pets/items/required",
+		Message:          `missing required property "name"`,
+	}}
+	if err == nil || err.Error() != want.Error() {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSchemaValidator_Struct(t *testing.T) {
+	type Pet struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	schema, err := openapi.SchemaFor[Pet]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema.Defs["Pet"].Properties["age"].Minimum = "0"
+	v, err := openapi.NewSchemaValidator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.Validate(schema, Pet{Name: "Rex", Age: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.Validate(schema, Pet{Name: "Rex", Age: -1})
+	if err == nil {
+		t.Error("expected an error for a negative age")
+	}
+}
+
+func TestSchemaValidator_DynamicRef(t *testing.T) {
+	doc := &openapi.OpenAPI{
+		Version: "3.1.0",
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Tree": {
+					ID:            "https://example.com/tree",
+					DynamicAnchor: "node",
+					Type:          openapi.Types{openapi.TypeObject},
+					Properties: map[string]*openapi.Schema{
+						"data":     openapi.BoolSchema(true),
+						"children": {Type: openapi.Types{openapi.TypeArray}, Items: &openapi.Schema{DynamicRef: "#node"}},
+					},
+				},
+				"StrictTree": {
+					ID:                    "https://example.com/strict-tree",
+					DynamicAnchor:         "node",
+					Ref:                   "tree",
+					UnevaluatedProperties: openapi.BoolSchema(false),
+				},
+			},
+		},
+	}
+	v, err := openapi.NewSchemaValidator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := map[string]any{"children": []any{map[string]any{"daat": 1}}}
+	err = v.Validate(doc.Components.Schemas["Tree"], value)
+	if err != nil {
+		t.Errorf("unexpected error for Tree: %v", err)
+	}
+	err = v.Validate(doc.Components.Schemas["StrictTree"], value)
+	var errs openapi.SchemaErrors
+	if !errors.As(err, &errs) || errs[0].InstanceLocation != "/children/0/daat" {
+		t.Errorf("unexpected error for StrictTree: %v", err)
+	}
+}
+
+func TestSchemaValidator_OAS30(t *testing.T) {
+	data, err := os.ReadFile("testdata/petstore-3.0.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.ReadYAML(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SchemaDialect() != openapi.DialectOAS30 {
+		t.Errorf("unexpected dialect: %s", doc.SchemaDialect())
+	}
+	v, err := openapi.NewSchemaValidator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		schema *openapi.Schema
+		value  any
+		want   []string
+	}{
+		{
+			name:   "nullable",
+			schema: &openapi.Schema{Type: openapi.Types{openapi.TypeString}, Nullable: true},
+			value:  nil,
+		},
+		{
+			name:   "not nullable",
+			schema: &openapi.Schema{Type: openapi.Types{openapi.TypeString}},
+			value:  nil,
+			want:   []string{"/type"},
+		},
+		{
+			name:   "boolean exclusiveMinimum",
+			schema: &openapi.Schema{Minimum: "0", MinimumExclusive: true},
+			value:  json.Number("0"),
+			want:   []string{"/exclusiveMinimum"},
+		},
+		{
+			name:   "ref siblings are ignored",
+			schema: &openapi.Schema{Ref: "#/components/schemas/Pet", Example: "Rex", Type: openapi.Types{openapi.TypeString}},
+			value:  map[string]any{"id": json.Number("1"), "name": "Rex", "tag": nil},
+		},
+		{
+			name:   "const is not a keyword",
+			schema: &openapi.Schema{Const: "cat"},
+			value:  "dog",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.schema, tt.value)
+			var got []string
+			var errs openapi.SchemaErrors
+			if errors.As(err, &errs) {
+				for _, e := range errs {
+					got = append(got, e.KeywordLocation)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected errors at %q, got:\n%v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_Dialect(t *testing.T) {
+	doc := &openapi.OpenAPI{Version: "3.1.0", JSONSchemaDialect: openapi.DialectJSONSchemaDraft4}
+	v, err := openapi.NewSchemaValidator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &openapi.Schema{Maximum: "10", MaximumExclusive: true}
+	err = v.Validate(schema, json.Number("10"))
+	if err == nil {
+		t.Error("expected an error for the exclusive maximum")
+	}
+
+	doc.JSONSchemaDialect = "https://example.com/dialect"
+	_, err = openapi.NewSchemaValidator(doc)
+	if err == nil {
+		t.Error("expected an error for an unsupported dialect")
+	}
+}
@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Scope is a scope of a security scheme, like an OAuth 2.0 scope or a role.
+type Scope struct {
+	// The name of the security scheme in the components.
+	Scheme string
+	Name   string
+}
+
+// ScopeUse is a use of a security scheme by a security requirement.
+type ScopeUse struct {
+	// JSON Pointer to the scheme in the requirement, like "/paths/~1pets/get/security/0/oauth".
+	Pointer string
+	// The operation, like "GET /pets", or empty for the top-level requirements.
+	Operation string
+	Scheme    string
+	// The scope required by the requirement. It's empty if the requirement lists no scopes.
+	Scope string
+}
+
+// ScopeReport is the cross-check of the scopes used by the security requirements
+// and the scopes declared by the OAuth 2.0 flows, produced by [OpenAPI.Scopes].
+type ScopeReport struct {
+	// Scopes of OAuth 2.0 schemes required by the security requirements but not declared by any flow of the scheme.
+	Undeclared []ScopeUse
+	// Scopes declared by the flows of OAuth 2.0 schemes but not required by any security requirement.
+	Unused []Scope
+	// Uses of the security schemes that aren't defined in the components.
+	UnknownSchemes []ScopeUse
+	// The operations that require each scope, like "GET /pets", in the order of the paths.
+	// Operations without their own requirements use the top-level ones. The declared scopes
+	// are included even if no operation requires them.
+	Operations map[Scope][]string
+}
+
+// Scopes cross-checks the scopes required by the security requirements of the operations
+// with the scopes declared by the flows of the OAuth 2.0 security schemes.
+//
+// Only the scopes of OAuth 2.0 schemes can be declared, so the scopes of other schemes,
+// like the roles of an API key, are never reported as undeclared.
+func (o *OpenAPI) Scopes() ScopeReport {
+	report := ScopeReport{Operations: make(map[Scope][]string)}
+	// The declared scopes of the OAuth 2.0 schemes by their names.
+	declared := make(map[string]map[string]string)
+	for name, ref := range o.Components.SecuritySchemes {
+		scheme, err := ref.Resolve(o)
+		if err == nil && scheme.Type == SecurityTypeOAuth2 {
+			declared[name] = scheme.Flows.scopes()
+			for scope := range declared[name] {
+				report.Operations[Scope{Scheme: name, Name: scope}] = nil
+			}
+		}
+	}
+	used := make(map[Scope]bool)
+	check := func(ptr, operation string, reqs []SecurityRequirement) {
+		for i, req := range reqs {
+			for _, name := range sortedKeys(req) {
+				use := ScopeUse{Pointer: at(ptr, strconv.Itoa(i), name), Operation: operation, Scheme: name}
+				if _, ok := o.Components.SecuritySchemes[name]; !ok {
+					report.UnknownSchemes = append(report.UnknownSchemes, use)
+					continue
+				}
+				for _, scope := range req[name] {
+					used[Scope{Scheme: name, Name: scope}] = true
+					scopes, oauth := declared[name]
+					if _, ok := scopes[scope]; oauth && !ok {
+						use.Scope = scope
+						report.Undeclared = append(report.Undeclared, use)
+					}
+				}
+			}
+		}
+	}
+	check("/security", "", o.Security)
+	for _, path := range sortedKeys(o.Paths.Items) {
+		for method, op := range o.Paths.Items[path].operations() {
+			if isZero(op) {
+				continue
+			}
+			operation := strings.ToUpper(method) + " " + path
+			reqs := o.Security
+			if op.Security != nil {
+				check(at("/paths", path, method, "security"), operation, op.Security)
+				reqs = op.Security
+			}
+			for _, req := range reqs {
+				for name, scopes := range req {
+					for _, scope := range scopes {
+						s := Scope{Scheme: name, Name: scope}
+						if !slices.Contains(report.Operations[s], operation) {
+							report.Operations[s] = append(report.Operations[s], operation)
+						}
+					}
+				}
+			}
+		}
+	}
+	for scope := range report.Operations {
+		if _, oauth := declared[scope.Scheme]; oauth && !used[scope] {
+			report.Unused = append(report.Unused, scope)
+		}
+	}
+	slices.SortFunc(report.Unused, compareScopes)
+	return report
+}
+
+func compareScopes(a, b Scope) int {
+	return cmp.Or(cmp.Compare(a.Scheme, b.Scheme), cmp.Compare(a.Name, b.Name))
+}
+
+// WriteCSV writes the scope to operations matrix as CSV with the columns
+// "scheme", "scope", and "operation", one row for each operation requiring a scope.
+// The scopes that no operation requires have a row with an empty operation.
+func (r ScopeReport) WriteCSV(w io.Writer) error {
+	scopes := slices.SortedFunc(maps.Keys(r.Operations), compareScopes)
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"scheme", "scope", "operation"})
+	for _, scope := range scopes {
+		ops := r.Operations[scope]
+		if len(ops) == 0 {
+			_ = out.Write([]string{scope.Scheme, scope.Name, ""})
+		}
+		for _, op := range ops {
+			_ = out.Write([]string{scope.Scheme, scope.Name, op})
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
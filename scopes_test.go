@@ -0,0 +1,98 @@
+package openapi_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestOpenAPI_Scopes(t *testing.T) {
+	ok := openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "OK"})}
+	doc := &openapi.OpenAPI{
+		Version:  "3.1.0",
+		Info:     openapi.Info{Title: "Pets", Version: "1.0"},
+		Security: []openapi.SecurityRequirement{{"oauth": {"read"}}},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets": {
+				Get: openapi.Operation{Responses: ok},
+				Post: openapi.Operation{
+					Security:  []openapi.SecurityRequirement{{"oauth": {"write", "pets:create"}}, {"key": {"admin"}}},
+					Responses: ok,
+				},
+			},
+			"/users": {
+				Get: openapi.Operation{
+					Security:  []openapi.SecurityRequirement{{"oauth": {"read"}, "basic": {}}},
+					Responses: ok,
+				},
+			},
+			"/health": {
+				Get: openapi.Operation{Security: []openapi.SecurityRequirement{}, Responses: ok},
+			},
+		}},
+		Components: openapi.Components{
+			SecuritySchemes: map[string]openapi.RefOr[openapi.SecurityScheme]{
+				"key": openapi.Inline(openapi.APIKeyScheme(openapi.InHeader, "X-API-Key")),
+				"oauth": openapi.Inline(openapi.OAuth2Scheme(openapi.OAuthFlows{
+					ClientCredentials: openapi.OAuthFlow{
+						TokenURL: "https://example.com/token",
+						Scopes:   map[string]string{"read": "Read", "write": "Write"},
+					},
+					AuthorizationCode: openapi.OAuthFlow{
+						AuthorizationURL: "https://example.com/auth",
+						TokenURL:         "https://example.com/token",
+						Scopes:           map[string]string{"read": "Read", "delete": "Delete"},
+					},
+				})),
+			},
+		},
+	}
+	report := doc.Scopes()
+
+	wantUndeclared := []openapi.ScopeUse{
+		{Pointer: "/paths/~1pets/post/security/0/oauth", Operation: "POST /pets", Scheme: "oauth", Scope: "pets:create"},
+	}
+	if !slices.Equal(report.Undeclared, wantUndeclared) {
+		t.Errorf("unexpected undeclared scopes: %v", report.Undeclared)
+	}
+	wantUnused := []openapi.Scope{{Scheme: "oauth", Name: "delete"}}
+	if !slices.Equal(report.Unused, wantUnused) {
+		t.Errorf("unexpected unused scopes: %v", report.Unused)
+	}
+	wantUnknown := []openapi.ScopeUse{
+		{Pointer: "/paths/~1users/get/security/0/basic", Operation: "GET /users", Scheme: "basic"},
+	}
+	if !slices.Equal(report.UnknownSchemes, wantUnknown) {
+		t.Errorf("unexpected unknown schemes: %v", report.UnknownSchemes)
+	}
+	wantOps := map[openapi.Scope][]string{
+		{Scheme: "oauth", Name: "read"}:        {"GET /pets", "GET /users"},
+		{Scheme: "oauth", Name: "write"}:       {"POST /pets"},
+		{Scheme: "oauth", Name: "pets:create"}: {"POST /pets"},
+		{Scheme: "oauth", Name: "delete"}:      nil,
+		{Scheme: "key", Name: "admin"}:         {"POST /pets"},
+	}
+	if !reflect.DeepEqual(report.Operations, wantOps) {
+		t.Errorf("unexpected operations: %v", report.Operations)
+	}
+
+	var csv strings.Builder
+	err := report.WriteCSV(&csv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `scheme,scope,operation
+key,admin,POST /pets
+oauth,delete,
+oauth,pets:create,POST /pets
+oauth,read,GET /pets
+oauth,read,GET /users
+oauth,write,POST /pets
+`
+	if csv.String() != want {
+		t.Errorf("unexpected CSV:\n%s", csv.String())
+	}
+}
@@ -0,0 +1,72 @@
+package openapi
+
+import "maps"
+
+// APIKeyScheme creates a security scheme for an API key sent in the header, query, or cookie parameter with the name.
+func APIKeyScheme(in Location, name string) SecurityScheme {
+	return SecurityScheme{Type: SecurityTypeAPIKey, In: in, Name: name}
+}
+
+// HTTPBearerScheme creates a security scheme for a bearer token sent in the Authorization header.
+//
+// The format is a hint of how the token is formatted, like "JWT". It can be empty.
+func HTTPBearerScheme(format string) SecurityScheme {
+	return SecurityScheme{Type: SecurityTypeHTTP, Scheme: "bearer", BearerFormat: format}
+}
+
+// OAuth2Scheme creates a security scheme for OAuth 2.0 with the supported flows.
+func OAuth2Scheme(flows OAuthFlows) SecurityScheme {
+	return SecurityScheme{Type: SecurityTypeOAuth2, Flows: flows}
+}
+
+// OpenIDConnectScheme creates a security scheme for OpenID Connect with the URL of its discovery document.
+func OpenIDConnectScheme(url string) SecurityScheme {
+	return SecurityScheme{Type: SecurityTypeOpenIDConnect, OpenIDConnectURL: url}
+}
+
+// MutualTLSScheme creates a security scheme for mutual TLS with client certificates.
+func MutualTLSScheme() SecurityScheme {
+	return SecurityScheme{Type: SecurityTypeMutualTLS}
+}
+
+// relevant returns the security scheme without the fields its type doesn't use.
+// Schemes without a known type are returned as is.
+func (s SecurityScheme) relevant() SecurityScheme {
+	result := SecurityScheme{Type: s.Type, Description: s.Description, Extensions: s.Extensions}
+	switch s.Type {
+	case SecurityTypeAPIKey:
+		result.Name = s.Name
+		result.In = s.In
+	case SecurityTypeHTTP:
+		result.Scheme = s.Scheme
+		result.BearerFormat = s.BearerFormat
+	case SecurityTypeOAuth2:
+		result.Flows = s.Flows
+	case SecurityTypeOpenIDConnect:
+		result.OpenIDConnectURL = s.OpenIDConnectURL
+	case SecurityTypeMutualTLS:
+	default:
+		return s
+	}
+	return result
+}
+
+// scopes returns the scopes declared by all the flows.
+func (f OAuthFlows) scopes() map[string]string {
+	scopes := make(map[string]string)
+	for _, flow := range []map[string]string{
+		f.Implicit.Scopes, f.Password.Scopes, f.ClientCredentials.Scopes, f.AuthorizationCode.Scopes,
+	} {
+		maps.Copy(scopes, flow)
+	}
+	return scopes
+}
+
+// nonNilScopes returns the scopes of an OAuth flow, or an empty map.
+// The scopes are required, so nil is encoded as an empty object.
+func nonNilScopes(scopes map[string]string) map[string]string {
+	if scopes == nil {
+		return map[string]string{}
+	}
+	return scopes
+}
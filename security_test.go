@@ -0,0 +1,94 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestSecurityScheme_JSON(t *testing.T) {
+	bearer := openapi.HTTPBearerScheme("JWT")
+	bearer.Name = "ignored"
+	tests := []struct {
+		scheme openapi.SecurityScheme
+		want   string
+	}{
+		{openapi.APIKeyScheme(openapi.InHeader, "X-API-Key"), `{"type":"apiKey","name":"X-API-Key","in":"header"}`},
+		{openapi.HTTPBearerScheme(""), `{"type":"http","scheme":"bearer"}`},
+		{bearer, `{"type":"http","scheme":"bearer","bearerFormat":"JWT"}`},
+		{
+			openapi.OAuth2Scheme(openapi.OAuthFlows{
+				ClientCredentials: openapi.OAuthFlow{TokenURL: "https://example.com/token"},
+			}),
+			`{"type":"oauth2","flows":{"clientCredentials":{"tokenUrl":"https://example.com/token","scopes":{}}}}`,
+		},
+		{
+			openapi.OAuth2Scheme(openapi.OAuthFlows{
+				Implicit: openapi.OAuthFlow{
+					AuthorizationURL: "https://example.com/auth",
+					TokenURL:         "https://example.com/ignored",
+					Scopes:           map[string]string{"read:pets": "Read pets"},
+				},
+				AuthorizationCode: openapi.OAuthFlow{
+					AuthorizationURL: "https://example.com/auth",
+					TokenURL:         "https://example.com/token",
+					RefreshURL:       "https://example.com/refresh",
+				},
+			}),
+			`{"type":"oauth2","flows":{"implicit":{"authorizationUrl":"https://example.com/auth","scopes":{"read:pets":"Read pets"}},` +
+				`"authorizationCode":{"authorizationUrl":"https://example.com/auth","tokenUrl":"https://example.com/token","refreshUrl":"https://example.com/refresh","scopes":{}}}}`,
+		},
+		{openapi.OpenIDConnectScheme("https://example.com/.well-known/openid-configuration"), `{"type":"openIdConnect","openIdConnectUrl":"https://example.com/.well-known/openid-configuration"}`},
+		{openapi.MutualTLSScheme(), `{"type":"mutualTLS"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.scheme)
+		if err != nil {
+			t.Errorf("%s: %v", tt.scheme.Type, err)
+			continue
+		}
+		if string(data) != tt.want {
+			t.Errorf("got %s, want %s", data, tt.want)
+		}
+		var got openapi.SecurityScheme
+		err = json.Unmarshal(data, &got)
+		if err != nil {
+			t.Errorf("%s: %v", tt.scheme.Type, err)
+			continue
+		}
+		again, _ := json.Marshal(got)
+		if string(again) != tt.want {
+			t.Errorf("unexpected round trip: %s", again)
+		}
+	}
+}
+
+func TestSecurityScheme_Validate(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Components: openapi.Components{
+			SecuritySchemes: map[string]openapi.RefOr[openapi.SecurityScheme]{
+				"oauth": openapi.Inline(openapi.OAuth2Scheme(openapi.OAuthFlows{
+					Password:          openapi.OAuthFlow{Scopes: map[string]string{}},
+					AuthorizationCode: openapi.OAuthFlow{TokenURL: "https://example.com/token"},
+				})),
+			},
+		},
+	}
+	err := doc.Validate()
+	var errs openapi.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := []openapi.ValidationError{
+		{Pointer: "/components/securitySchemes/oauth/flows/password/tokenUrl", Message: "the token URL is required"},
+		{Pointer: "/components/securitySchemes/oauth/flows/authorizationCode/authorizationUrl", Message: "the authorization URL is required"},
+	}
+	if !slices.Equal(errs, want) {
+		t.Errorf("unexpected violations:\n%v", errs)
+	}
+}
@@ -0,0 +1,383 @@
+package openapi
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// SecurityEnforcement configures how [EnforceSecurity] authenticates requests.
+type SecurityEnforcement struct {
+	// Authenticators by the names of the security schemes in the components.
+	// Each scheme used by a security requirement must have one.
+	Authenticators map[string]Authenticator
+	// The protection space sent in the WWW-Authenticate challenges. If empty, it's the title of the API.
+	Realm string
+}
+
+// Authenticator verifies the credentials of a request for a security scheme.
+//
+// It returns the authenticated principal, including the scopes granted to it,
+// or an error if the credentials are invalid.
+type Authenticator func(r *http.Request, c Credentials) (Principal, error)
+
+// Credentials extracted from a request for a security scheme.
+type Credentials struct {
+	// The name of the security scheme in the components.
+	Scheme         string
+	SecurityScheme SecurityScheme
+	// The API key for "apiKey", or the token from the Authorization header for "oauth2",
+	// "openIdConnect", and "http" schemes other than "basic", like "bearer".
+	Token string
+	// The user name and the password for the "basic" HTTP scheme.
+	Username string
+	Password string
+	// The client certificates for "mutualTLS", the leaf first.
+	Certificates []*x509.Certificate
+}
+
+// Principal is who made the request, as authenticated by an [Authenticator].
+type Principal struct {
+	// The name of the security scheme that authenticated the principal. It's set by [EnforceSecurity].
+	Scheme string
+	// The identity of the principal, like a user or the claims of a token.
+	Identity any
+	// The scopes granted to the principal. For OAuth 2.0, they are the scopes of the access token.
+	// For other schemes, they are the roles the security requirements may list.
+	Scopes []string
+}
+
+type principalsKey struct{}
+
+// Principals returns the principals authenticated by [EnforceSecurity] for the request,
+// one for each scheme of the satisfied security requirement, sorted by the scheme name.
+// It's empty if the operation doesn't require authentication.
+func Principals(ctx context.Context) []Principal {
+	principals, _ := ctx.Value(principalsKey{}).([]Principal)
+	return principals
+}
+
+// EnforceSecurity returns a middleware that authenticates requests
+// by the security requirements of their operations.
+//
+// The requirements of the operation, or the top-level ones if the operation has none,
+// are alternatives: the request must satisfy at least one of them. A requirement is satisfied
+// if the credentials of all its schemes are accepted by the authenticators
+// and the principals have all the scopes it lists.
+// The credentials are taken from the request as the security scheme describes:
+// an API key from the header, query, or cookie, the Authorization header
+// for HTTP schemes, OAuth 2.0 and OpenID Connect, or the client certificates for mutual TLS.
+//
+// If no requirement is satisfied, the response is "401 Unauthorized" with WWW-Authenticate challenges,
+// or "403 Forbidden" if the principals are authenticated but lack the scopes. The responses
+// have [Problem] details. Otherwise, the principals are available to the next handler with [Principals].
+// Requests that don't match any operation are passed to the next handler as is.
+//
+// It panics if a security requirement refers to a scheme that isn't defined or has no authenticator.
+func EnforceSecurity(doc *OpenAPI, opts SecurityEnforcement) func(http.Handler) http.Handler {
+	e, err := newSecurityEnforcer(doc, opts)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: enforce security: %v", err))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, err := e.router.Match(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			principals, ok := e.authenticate(w, r, e.requirements(route.Operation))
+			if !ok {
+				return
+			}
+			if len(principals) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), principalsKey{}, principals))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// securityEnforcer authenticates requests by the security requirements of the document.
+type securityEnforcer struct {
+	doc    *OpenAPI
+	router *Router
+	realm  string
+	// Resolved security schemes by their names.
+	schemes        map[string]SecurityScheme
+	authenticators map[string]Authenticator
+}
+
+func newSecurityEnforcer(doc *OpenAPI, opts SecurityEnforcement) (*securityEnforcer, error) {
+	e := &securityEnforcer{
+		doc:            doc,
+		router:         NewRouter(doc),
+		realm:          opts.Realm,
+		schemes:        make(map[string]SecurityScheme),
+		authenticators: opts.Authenticators,
+	}
+	if e.realm == "" {
+		e.realm = doc.Info.Title
+	}
+	reqs := slices.Clone(doc.Security)
+	for _, item := range doc.Paths.Items {
+		for _, op := range item.operations() {
+			reqs = append(reqs, op.Security...)
+		}
+	}
+	for _, req := range reqs {
+		for name := range req {
+			if _, ok := e.schemes[name]; ok {
+				continue
+			}
+			ref, ok := doc.Components.SecuritySchemes[name]
+			if !ok {
+				return nil, fmt.Errorf("the security scheme %q is not defined", name)
+			}
+			scheme, err := ref.Resolve(doc)
+			if err != nil {
+				return nil, fmt.Errorf("security scheme %s: %w", name, err)
+			}
+			if e.authenticators[name] == nil {
+				return nil, fmt.Errorf("no authenticator for the security scheme %q", name)
+			}
+			e.schemes[name] = scheme
+		}
+	}
+	return e, nil
+}
+
+// requirements returns the security requirements of the operation, or the top-level ones if it has none.
+func (e *securityEnforcer) requirements(op Operation) []SecurityRequirement {
+	if op.Security != nil {
+		return op.Security
+	}
+	return e.doc.Security
+}
+
+// authentication is the result of authenticating a request for a security scheme.
+type authentication struct {
+	principal Principal
+	// Credentials are missing in the request.
+	missing bool
+	err     error
+}
+
+// authenticate checks the security requirements for the request. It returns the principals
+// of the first satisfied requirement, trying the empty requirement last, so that the credentials
+// of an optionally authenticated request are still verified. If none is satisfied,
+// it writes the error response and returns false.
+func (e *securityEnforcer) authenticate(w http.ResponseWriter, r *http.Request, reqs []SecurityRequirement) ([]Principal, bool) {
+	if len(reqs) == 0 {
+		return nil, true
+	}
+	// Each scheme is authenticated once, even if it's used by many requirements.
+	results := make(map[string]authentication)
+	var missingScopes []string
+	forbidden := false
+	// The empty requirement allows anonymous requests, so it's tried last
+	// to authenticate the request with the other requirements if it has credentials.
+	ordered := slices.SortedStableFunc(slices.Values(reqs), func(a, b SecurityRequirement) int {
+		return boolToInt(len(a) == 0) - boolToInt(len(b) == 0)
+	})
+	for _, req := range ordered {
+		principals := make([]Principal, 0, len(req))
+		var missing []string
+		satisfied := true
+		for _, name := range sortedKeys(req) {
+			result, ok := results[name]
+			if !ok {
+				result = e.authenticateScheme(r, name)
+				results[name] = result
+			}
+			if result.missing || result.err != nil {
+				satisfied = false
+				break
+			}
+			for _, scope := range req[name] {
+				if !slices.Contains(result.principal.Scopes, scope) {
+					missing = append(missing, scope)
+				}
+			}
+			principals = append(principals, result.principal)
+		}
+		if !satisfied {
+			continue
+		}
+		if len(missing) == 0 {
+			return principals, true
+		}
+		if !forbidden {
+			forbidden = true
+			missingScopes = missing
+		}
+	}
+	if forbidden {
+		for _, challenge := range e.challenges(reqs, results, missingScopes) {
+			w.Header().Add("WWW-Authenticate", challenge)
+		}
+		writeProblem(w, Problem{
+			Status: http.StatusForbidden,
+			Detail: fmt.Sprintf("the request requires the scopes %s", strings.Join(missingScopes, ", ")),
+		})
+		return nil, false
+	}
+	for _, challenge := range e.challenges(reqs, results, nil) {
+		w.Header().Add("WWW-Authenticate", challenge)
+	}
+	detail := "the request requires authentication"
+	for _, result := range results {
+		if result.err != nil {
+			detail = "the credentials are invalid"
+			break
+		}
+	}
+	writeProblem(w, Problem{Status: http.StatusUnauthorized, Detail: detail})
+	return nil, false
+}
+
+// authenticateScheme extracts the credentials of the scheme from the request and verifies them.
+func (e *securityEnforcer) authenticateScheme(r *http.Request, name string) authentication {
+	scheme := e.schemes[name]
+	creds, ok := credentials(r, scheme)
+	if !ok {
+		return authentication{missing: true}
+	}
+	creds.Scheme = name
+	creds.SecurityScheme = scheme
+	principal, err := e.authenticators[name](r, creds)
+	if err != nil {
+		return authentication{err: err}
+	}
+	principal.Scheme = name
+	return authentication{principal: principal}
+}
+
+// credentials extracts the credentials for the security scheme from the request.
+// It returns false if they are missing.
+func credentials(r *http.Request, scheme SecurityScheme) (Credentials, bool) {
+	var creds Credentials
+	switch scheme.Type {
+	case SecurityTypeAPIKey:
+		switch scheme.In {
+		case InHeader:
+			creds.Token = r.Header.Get(scheme.Name)
+		case InQuery:
+			creds.Token = r.URL.Query().Get(scheme.Name)
+		case InCookie:
+			if cookie, err := r.Cookie(scheme.Name); err == nil {
+				creds.Token = cookie.Value
+			}
+		}
+		return creds, creds.Token != ""
+	case SecurityTypeHTTP:
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			var ok bool
+			creds.Username, creds.Password, ok = r.BasicAuth()
+			return creds, ok
+		}
+		creds.Token = authorization(r, scheme.Scheme)
+		return creds, creds.Token != ""
+	case SecurityTypeOAuth2, SecurityTypeOpenIDConnect:
+		creds.Token = authorization(r, "bearer")
+		return creds, creds.Token != ""
+	case SecurityTypeMutualTLS:
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return creds, false
+		}
+		creds.Certificates = r.TLS.PeerCertificates
+		return creds, true
+	}
+	return creds, false
+}
+
+// authorization returns the credentials from the Authorization header for the HTTP authentication scheme.
+// The scheme is case-insensitive.
+func authorization(r *http.Request, scheme string) string {
+	auth := r.Header.Get("Authorization")
+	prefix, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(prefix, scheme) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// challenges returns the WWW-Authenticate challenges for the schemes of the requirements, one per authentication scheme.
+//
+// Bearer challenges have the error defined by RFC 6750: "invalid_token" if a token is rejected,
+// or "insufficient_scope" with the missing scopes if they are given, and then the other
+// schemes have no challenges. API keys get an "APIKey" challenge with the location and the name
+// of the key. Mutual TLS has no challenge.
+func (e *securityEnforcer) challenges(reqs []SecurityRequirement, results map[string]authentication, missingScopes []string) []string {
+	var names []string
+	for _, req := range reqs {
+		for _, name := range sortedKeys(req) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	var challenges []string
+	// The indices of the challenges by their lowercase authentication schemes.
+	index := make(map[string]int)
+	// If the challenge has an error parameter. Such a challenge is preferred
+	// over the one without for the same authentication scheme.
+	var withError []bool
+	for _, name := range names {
+		scheme := e.schemes[name]
+		var authScheme string
+		params := []string{"realm=" + quoteParam(e.realm)}
+		switch scheme.Type {
+		case SecurityTypeAPIKey:
+			authScheme = "APIKey"
+			params = append(params, "in="+quoteParam(string(scheme.In)), "name="+quoteParam(scheme.Name))
+		case SecurityTypeHTTP:
+			authScheme = scheme.Scheme
+			if strings.EqualFold(authScheme, "basic") {
+				authScheme = "Basic"
+				params = append(params, `charset="UTF-8"`)
+			}
+		case SecurityTypeOAuth2, SecurityTypeOpenIDConnect:
+			authScheme = "Bearer"
+		default:
+			continue
+		}
+		if !strings.EqualFold(authScheme, "bearer") && len(missingScopes) > 0 {
+			// Only bearer tokens have a challenge for the missing scopes.
+			continue
+		}
+		hasError := false
+		if strings.EqualFold(authScheme, "bearer") {
+			authScheme = "Bearer"
+			hasError = len(missingScopes) > 0 || results[name].err != nil
+			switch {
+			case len(missingScopes) > 0:
+				params = append(params, `error="insufficient_scope"`, "scope="+quoteParam(strings.Join(missingScopes, " ")))
+			case results[name].err != nil:
+				params = append(params, `error="invalid_token"`)
+			}
+		}
+		challenge := authScheme + " " + strings.Join(params, ", ")
+		key := strings.ToLower(authScheme)
+		if i, ok := index[key]; ok {
+			if hasError && !withError[i] {
+				challenges[i] = challenge
+				withError[i] = true
+			}
+			continue
+		}
+		index[key] = len(challenges)
+		challenges = append(challenges, challenge)
+		withError = append(withError, hasError)
+	}
+	return challenges
+}
+
+// quoteParam quotes the value of an authentication parameter as defined by RFC 9110.
+func quoteParam(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
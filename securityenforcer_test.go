@@ -0,0 +1,242 @@
+package openapi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func securedAPI() *openapi.OpenAPI {
+	ok := openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "OK"})}
+	return &openapi.OpenAPI{
+		Version:  "3.1.0",
+		Info:     openapi.Info{Title: "Pets", Version: "1.0"},
+		Security: []openapi.SecurityRequirement{{"basic": {}}},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets": {
+				Get: openapi.Operation{
+					Security:  []openapi.SecurityRequirement{{"key": {}}, {"oauth": {"read"}}},
+					Responses: ok,
+				},
+				Post: openapi.Operation{
+					Security:  []openapi.SecurityRequirement{{"oauth": {"write"}}},
+					Responses: ok,
+				},
+			},
+			"/pets/{id}": {
+				Get: openapi.Operation{
+					Parameters: []openapi.RefOr[openapi.Parameter]{openapi.Inline(openapi.Parameter{
+						Name: "id", In: openapi.InPath, Required: ptr(true), Schema: &openapi.Schema{},
+					})},
+					Responses: ok,
+				},
+			},
+			"/admin": {
+				Get: openapi.Operation{
+					Security:  []openapi.SecurityRequirement{{"key": {}, "basic": {}}},
+					Responses: ok,
+				},
+			},
+			"/health": {
+				Get: openapi.Operation{Security: []openapi.SecurityRequirement{}, Responses: ok},
+			},
+		}},
+		Components: openapi.Components{
+			SecuritySchemes: map[string]openapi.RefOr[openapi.SecurityScheme]{
+				"key":   openapi.Inline(openapi.APIKeyScheme(openapi.InHeader, "X-API-Key")),
+				"basic": openapi.Inline(openapi.SecurityScheme{Type: openapi.SecurityTypeHTTP, Scheme: "basic"}),
+				"oauth": openapi.Inline(openapi.OAuth2Scheme(openapi.OAuthFlows{
+					ClientCredentials: openapi.OAuthFlow{
+						TokenURL: "https://example.com/token",
+						Scopes:   map[string]string{"read": "Read pets", "write": "Write pets"},
+					},
+				})),
+			},
+		},
+	}
+}
+
+func TestEnforceSecurity(t *testing.T) {
+	authenticators := map[string]openapi.Authenticator{
+		"key": func(r *http.Request, c openapi.Credentials) (openapi.Principal, error) {
+			if c.Token != "secret" {
+				return openapi.Principal{}, errors.New("unknown key")
+			}
+			return openapi.Principal{Identity: "service"}, nil
+		},
+		"basic": func(r *http.Request, c openapi.Credentials) (openapi.Principal, error) {
+			if c.Username != "alice" || c.Password != "pw" {
+				return openapi.Principal{}, errors.New("wrong password")
+			}
+			return openapi.Principal{Identity: c.Username}, nil
+		},
+		"oauth": func(r *http.Request, c openapi.Credentials) (openapi.Principal, error) {
+			switch c.Token {
+			case "r":
+				return openapi.Principal{Identity: "reader", Scopes: []string{"read"}}, nil
+			case "rw":
+				return openapi.Principal{Identity: "writer", Scopes: []string{"read", "write"}}, nil
+			}
+			return openapi.Principal{}, errors.New("unknown token")
+		},
+	}
+	doc := securedAPI()
+	ok := openapi.Responses{OK: openapi.Inline(openapi.Response{Description: "OK"})}
+	doc.Paths.Items["/feed"] = openapi.PathItem{Get: openapi.Operation{
+		Security:  []openapi.SecurityRequirement{{}, {"oauth": {"read"}}},
+		Responses: ok,
+	}}
+	doc.Paths.Items["/tokens"] = openapi.PathItem{Get: openapi.Operation{
+		Security:  []openapi.SecurityRequirement{{"key": {}, "token": {}}, {"oauth": {}}},
+		Responses: ok,
+	}}
+	doc.Components.SecuritySchemes["token"] = openapi.Inline(openapi.HTTPBearerScheme(""))
+	authenticators["token"] = authenticators["oauth"]
+	handler := openapi.EnforceSecurity(doc, openapi.SecurityEnforcement{Authenticators: authenticators})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var names []string
+			for _, p := range openapi.Principals(r.Context()) {
+				names = append(names, p.Scheme+":"+p.Identity.(string))
+			}
+			_, _ = w.Write([]byte(strings.Join(names, ",")))
+		}),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header map[string]string
+		status int
+		// The body for successful requests, or the WWW-Authenticate challenges.
+		want []string
+	}{
+		{
+			name: "no credentials", method: "GET", path: "/pets",
+			status: http.StatusUnauthorized,
+			want:   []string{`APIKey realm="Pets", in="header", name="X-API-Key"`, `Bearer realm="Pets"`},
+		},
+		{
+			name: "API key", method: "GET", path: "/pets",
+			header: map[string]string{"X-API-Key": "secret"},
+			status: http.StatusOK,
+			want:   []string{"key:service"},
+		},
+		{
+			name: "invalid API key", method: "GET", path: "/pets",
+			header: map[string]string{"X-API-Key": "guess"},
+			status: http.StatusUnauthorized,
+			want:   []string{`APIKey realm="Pets", in="header", name="X-API-Key"`, `Bearer realm="Pets"`},
+		},
+		{
+			name: "alternative requirement", method: "GET", path: "/pets",
+			header: map[string]string{"Authorization": "bearer r"},
+			status: http.StatusOK,
+			want:   []string{"oauth:reader"},
+		},
+		{
+			name: "invalid token", method: "GET", path: "/pets",
+			header: map[string]string{"Authorization": "Bearer x"},
+			status: http.StatusUnauthorized,
+			want:   []string{`APIKey realm="Pets", in="header", name="X-API-Key"`, `Bearer realm="Pets", error="invalid_token"`},
+		},
+		{
+			name: "missing scope", method: "POST", path: "/pets",
+			header: map[string]string{"Authorization": "Bearer r"},
+			status: http.StatusForbidden,
+			want:   []string{`Bearer realm="Pets", error="insufficient_scope", scope="write"`},
+		},
+		{
+			name: "scope", method: "POST", path: "/pets",
+			header: map[string]string{"Authorization": "Bearer rw"},
+			status: http.StatusOK,
+			want:   []string{"oauth:writer"},
+		},
+		{
+			name: "top-level requirement", method: "GET", path: "/pets/1",
+			status: http.StatusUnauthorized,
+			want:   []string{`Basic realm="Pets", charset="UTF-8"`},
+		},
+		{
+			name: "basic", method: "GET", path: "/pets/1",
+			header: map[string]string{"Authorization": "Basic YWxpY2U6cHc="},
+			status: http.StatusOK,
+			want:   []string{"basic:alice"},
+		},
+		{
+			name: "all schemes of a requirement", method: "GET", path: "/admin",
+			header: map[string]string{"Authorization": "Basic YWxpY2U6cHc=", "X-API-Key": "secret"},
+			status: http.StatusOK,
+			want:   []string{"basic:alice,key:service"},
+		},
+		{
+			name: "some schemes of a requirement", method: "GET", path: "/admin",
+			header: map[string]string{"X-API-Key": "secret"},
+			status: http.StatusUnauthorized,
+			want:   []string{`Basic realm="Pets", charset="UTF-8"`, `APIKey realm="Pets", in="header", name="X-API-Key"`},
+		},
+		{
+			name: "optional authentication", method: "GET", path: "/feed",
+			status: http.StatusOK,
+			want:   []string{""},
+		},
+		{
+			name: "optional authentication with credentials", method: "GET", path: "/feed",
+			header: map[string]string{"Authorization": "Bearer r"},
+			status: http.StatusOK,
+			want:   []string{"oauth:reader"},
+		},
+		{
+			name: "challenge with an error", method: "GET", path: "/tokens",
+			header: map[string]string{"Authorization": "Bearer x"},
+			status: http.StatusUnauthorized,
+			want:   []string{`APIKey realm="Pets", in="header", name="X-API-Key"`, `Bearer realm="Pets", error="invalid_token"`},
+		},
+		{
+			name: "public", method: "GET", path: "/health",
+			status: http.StatusOK,
+			want:   []string{""},
+		},
+		{
+			name: "unknown path", method: "GET", path: "/unknown",
+			status: http.StatusOK,
+			want:   []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			for key, value := range tt.header {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			got := w.Header().Values("WWW-Authenticate")
+			if w.Code == http.StatusOK {
+				got = []string{w.Body.String()}
+			} else if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("unexpected content type %q", ct)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnforceSecurity_MissingAuthenticator(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	openapi.EnforceSecurity(securedAPI(), openapi.SecurityEnforcement{})
+}
@@ -0,0 +1,723 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A violation of the OpenAPI Specification rules found by [OpenAPI.Validate].
+type ValidationError struct {
+	// JSON Pointer to the invalid value in the document, like "/paths/~1pets/get/responses/200/description".
+	Pointer string
+	// The description of the violated rule.
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+// All the violations found by [OpenAPI.Validate].
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Validate checks the document against the structural rules of the OpenAPI Specification.
+//
+// It checks that the required fields are set, mutually exclusive fields aren't set together,
+// path templates match the declared path parameters, parameters aren't duplicated,
+// operation IDs are unique, and local references point to existing values.
+//
+// If the document is invalid, the returned error is [ValidationErrors].
+func (o *OpenAPI) Validate() error {
+	v := docValidator{doc: o, operationIDs: make(map[string]string)}
+	v.validate()
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+var (
+	pathTemplateRe  = regexp.MustCompile(`\{([^{}]*)\}`)
+	componentNameRe = regexp.MustCompile(`^[a-zA-Z0-9\.\-_]+$`)
+)
+
+type docValidator struct {
+	doc  *OpenAPI
+	errs ValidationErrors
+	// Pointers to operations by their IDs.
+	operationIDs map[string]string
+	// The document as a generic JSON value, used to resolve references.
+	// It's nil if the document can't be encoded.
+	raw any
+}
+
+func (v *docValidator) report(ptr string, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+}
+
+// at appends the tokens to the JSON Pointer.
+func at(ptr string, tokens ...string) string {
+	for _, token := range tokens {
+		ptr += "/" + escapePointer(token)
+	}
+	return ptr
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+func (v *docValidator) validate() {
+	// References can't be resolved without the encoded document,
+	// but the rest of the checks work on the typed one.
+	data, err := json.Marshal(v.doc)
+	if err != nil {
+		v.report("", "cannot encode the document: %v", err)
+	} else if err = decodeJSON(data, &v.raw); err != nil {
+		v.report("", "cannot decode the document: %v", err)
+	}
+
+	doc := v.doc
+	if doc.Version == "" {
+		v.report("/openapi", "the OpenAPI version is required")
+	}
+	v.info(doc.Info)
+	v.servers("/servers", doc.Servers)
+	v.paths(doc.Paths)
+	for _, name := range sortedKeys(doc.Webhooks) {
+		v.pathItem(at("/webhooks", name), doc.Webhooks[name], nil)
+	}
+	v.components(doc.Components)
+	v.security("/security", doc.Security)
+	tags := make(map[string]bool)
+	for i, tag := range doc.Tags {
+		ptr := at("/tags", strconv.Itoa(i))
+		if tag.Name == "" {
+			v.report(at(ptr, "name"), "the tag name is required")
+		} else if tags[tag.Name] {
+			v.report(at(ptr, "name"), "duplicate tag name %q", tag.Name)
+		}
+		tags[tag.Name] = true
+		v.externalDoc(at(ptr, "externalDocs"), tag.ExternalDocs)
+	}
+	v.externalDoc("/externalDocs", doc.ExternalDocs)
+}
+
+func (v *docValidator) info(info Info) {
+	if info.Title == "" {
+		v.report("/info/title", "the API title is required")
+	}
+	if info.Version == "" {
+		v.report("/info/version", "the API version is required")
+	}
+	license := info.License
+	if license.Name == "" && (license.Identifier != "" || license.URL != "" || len(license.Extensions) > 0) {
+		v.report("/info/license/name", "the license name is required")
+	}
+	if license.Identifier != "" && license.URL != "" {
+		v.report("/info/license", "identifier and url are mutually exclusive")
+	}
+}
+
+func (v *docValidator) servers(ptr string, servers []Server) {
+	for i, server := range servers {
+		ptr := at(ptr, strconv.Itoa(i))
+		if server.URL == "" {
+			v.report(at(ptr, "url"), "the server URL is required")
+		}
+		for _, match := range pathTemplateRe.FindAllStringSubmatch(server.URL, -1) {
+			if _, ok := server.Variables[match[1]]; !ok {
+				v.report(at(ptr, "url"), "the server variable %q is not defined", match[1])
+			}
+		}
+		for _, name := range sortedKeys(server.Variables) {
+			variable := server.Variables[name]
+			ptr := at(ptr, "variables", name)
+			if variable.Enum != nil && len(variable.Enum) == 0 {
+				v.report(at(ptr, "enum"), "the enum must not be empty")
+			}
+			if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, variable.Default) {
+				v.report(at(ptr, "default"), "the default value %q is not in the enum", variable.Default)
+			}
+		}
+	}
+}
+
+func (v *docValidator) paths(paths Paths) {
+	// Paths that differ only in the names of the template expressions.
+	templates := make(map[string]string)
+	for _, path := range sortedKeys(paths.Items) {
+		ptr := at("/paths", path)
+		if !strings.HasPrefix(path, "/") {
+			v.report(ptr, "the path must begin with a forward slash")
+		}
+		template := pathTemplateRe.ReplaceAllString(path, "{}")
+		if other, ok := templates[template]; ok {
+			v.report(ptr, "the path is equivalent to %q", other)
+		}
+		templates[template] = path
+		var names []string
+		for _, match := range pathTemplateRe.FindAllStringSubmatch(path, -1) {
+			names = append(names, match[1])
+		}
+		if names == nil {
+			names = []string{}
+		}
+		v.pathItem(ptr, paths.Items[path], names)
+	}
+}
+
+// pathItem validates the path item. The templates are names of the path template expressions,
+// or nil if the path item isn't in Paths.
+func (v *docValidator) pathItem(ptr string, item PathItem, templates []string) {
+	v.ref(ptr, item.Ref)
+	v.servers(at(ptr, "servers"), item.Servers)
+	common := v.parameters(at(ptr, "parameters"), item.Parameters)
+	for method, op := range item.operations() {
+		if isZero(op) {
+			continue
+		}
+		v.operation(at(ptr, method), op, common, templates)
+	}
+}
+
+// operations iterates over the operations of the path item together with the lowercase method names.
+func (p PathItem) operations() func(yield func(string, Operation) bool) {
+	return func(yield func(string, Operation) bool) {
+		_ = yield("get", p.Get) &&
+			yield("put", p.Put) &&
+			yield("post", p.Post) &&
+			yield("delete", p.Delete) &&
+			yield("options", p.Options) &&
+			yield("head", p.Head) &&
+			yield("patch", p.Patch) &&
+			yield("trace", p.Trace)
+	}
+}
+
+func isZero[T any](v T) bool {
+	return reflect.ValueOf(&v).Elem().IsZero()
+}
+
+func (v *docValidator) operation(ptr string, op Operation, common []Parameter, templates []string) {
+	if op.OperationID != "" {
+		if other, ok := v.operationIDs[op.OperationID]; ok {
+			v.report(at(ptr, "operationId"), "duplicate operation ID %q, also used by %s", op.OperationID, other)
+		} else {
+			v.operationIDs[op.OperationID] = ptr
+		}
+	}
+	v.externalDoc(at(ptr, "externalDocs"), op.ExternalDocs)
+	params := v.parameters(at(ptr, "parameters"), op.Parameters)
+	if templates != nil {
+		v.pathParameters(ptr, templates, common, params)
+	}
+	if !op.RequestBody.IsZero() {
+		ptr := at(ptr, "requestBody")
+		if v.ref(ptr, op.RequestBody.Ref) {
+			v.requestBody(ptr, op.RequestBody.Value)
+		}
+	}
+	v.responses(at(ptr, "responses"), op.Responses)
+	for _, name := range sortedKeys(op.Callbacks) {
+		ptr := at(ptr, "callbacks", name)
+		callback := op.Callbacks[name]
+		if v.ref(ptr, callback.Ref) {
+			v.callback(ptr, callback.Value)
+		}
+	}
+	if op.Security != nil {
+		v.security(at(ptr, "security"), op.Security)
+	}
+	v.servers(at(ptr, "servers"), op.Servers)
+}
+
+// pathParameters checks that the path template expressions match the path parameters.
+func (v *docValidator) pathParameters(ptr string, templates []string, common, params []Parameter) {
+	declared := make(map[string]bool)
+	for _, p := range slices.Concat(common, params) {
+		if p.In != InPath {
+			continue
+		}
+		if !declared[p.Name] && !slices.Contains(templates, p.Name) {
+			v.report(ptr, "the path parameter %q is not in the path template", p.Name)
+		}
+		declared[p.Name] = true
+	}
+	for _, name := range templates {
+		if !declared[name] {
+			v.report(ptr, "the path template expression %q has no matching path parameter", name)
+		}
+	}
+}
+
+// parameters validates the list of parameters and returns the resolved ones.
+func (v *docValidator) parameters(ptr string, params []RefOr[Parameter]) []Parameter {
+	type key struct {
+		name string
+		in   Location
+	}
+	seen := make(map[key]bool)
+	var resolved []Parameter
+	for i, param := range params {
+		ptr := at(ptr, strconv.Itoa(i))
+		inline := v.ref(ptr, param.Ref)
+		// Referenced parameters are validated in the components,
+		// but still count for the path template and duplicates.
+		p, err := param.Resolve(v.doc)
+		if err != nil {
+			// Reported by ref, unless the document can't be encoded.
+			continue
+		}
+		if inline {
+			v.parameter(ptr, p)
+		}
+		k := key{p.Name, p.In}
+		if p.In == InHeader {
+			k.name = strings.ToLower(p.Name)
+		}
+		if seen[k] {
+			v.report(ptr, "duplicate parameter %q in %s", p.Name, p.In)
+		}
+		seen[k] = true
+		resolved = append(resolved, p)
+	}
+	return resolved
+}
+
+func (v *docValidator) parameter(ptr string, p Parameter) {
+	if p.Name == "" {
+		v.report(at(ptr, "name"), "the parameter name is required")
+	}
+	switch p.In {
+	case "":
+		v.report(at(ptr, "in"), "the parameter location is required")
+	case InQuery, InHeader, InCookie:
+	case InPath:
+		if !p.RequiredOrDefault() {
+			v.report(at(ptr, "required"), "path parameters must be required")
+		}
+	default:
+		v.report(at(ptr, "in"), "invalid parameter location %q", p.In)
+	}
+	if p.Style != "" && slices.Contains(locations, p.In) && !p.Style.AllowedIn(p.In) {
+		v.report(at(ptr, "style"), "the style %q is not allowed in %s", p.Style, p.In)
+	}
+	if p.AllowEmptyValueOrDefault() && p.In != InQuery {
+		v.report(at(ptr, "allowEmptyValue"), "allowEmptyValue is valid only for query parameters")
+	}
+	if p.AllowReserved && p.In != InQuery {
+		v.report(at(ptr, "allowReserved"), "allowReserved is valid only for query parameters")
+	}
+	v.schemaOrContent(ptr, p.Schema, p.Content)
+	v.examples(ptr, p.Example, p.Examples)
+}
+
+func (v *docValidator) header(ptr string, h Header) {
+	if h.Style != "" && h.Style != StyleSimple {
+		v.report(at(ptr, "style"), "the style %q is not allowed in headers", h.Style)
+	}
+	v.schemaOrContent(ptr, h.Schema, h.Content)
+	v.examples(ptr, h.Example, h.Examples)
+}
+
+// schemaOrContent checks the fields of a parameter or a header that describe its type.
+func (v *docValidator) schemaOrContent(ptr string, schema *Schema, content map[string]MediaType) {
+	if schema != nil && content != nil {
+		v.report(ptr, "schema and content are mutually exclusive")
+	}
+	if schema == nil && content == nil {
+		v.report(ptr, "either schema or content is required")
+	}
+	if content != nil && len(content) != 1 {
+		v.report(at(ptr, "content"), "the content must contain exactly one entry")
+	}
+	v.schema(at(ptr, "schema"), schema)
+	v.content(at(ptr, "content"), content)
+}
+
+func (v *docValidator) examples(ptr string, example any, examples map[string]RefOr[Example]) {
+	if example != nil && examples != nil {
+		v.report(ptr, "example and examples are mutually exclusive")
+	}
+	for _, name := range sortedKeys(examples) {
+		ptr := at(ptr, "examples", name)
+		ex := examples[name]
+		if v.ref(ptr, ex.Ref) {
+			v.example(ptr, ex.Value)
+		}
+	}
+}
+
+func (v *docValidator) example(ptr string, ex Example) {
+	if ex.Value != nil && ex.ExternalValue != "" {
+		v.report(ptr, "value and externalValue are mutually exclusive")
+	}
+}
+
+func (v *docValidator) content(ptr string, content map[string]MediaType) {
+	for _, mediaType := range sortedKeys(content) {
+		ptr := at(ptr, mediaType)
+		mt := content[mediaType]
+		v.schema(at(ptr, "schema"), mt.Schema)
+		v.examples(ptr, mt.Example, mt.Examples)
+		for _, name := range sortedKeys(mt.Encoding) {
+			enc := mt.Encoding[name]
+			if enc.Style != "" && !enc.Style.AllowedIn(InQuery) {
+				v.report(at(ptr, "encoding", name, "style"), "the style %q is not allowed in encodings", enc.Style)
+			}
+			v.headers(at(ptr, "encoding", name, "headers"), enc.Headers)
+		}
+	}
+}
+
+func (v *docValidator) headers(ptr string, headers map[string]RefOr[Header]) {
+	for _, name := range sortedKeys(headers) {
+		ptr := at(ptr, name)
+		h := headers[name]
+		if v.ref(ptr, h.Ref) {
+			v.header(ptr, h.Value)
+		}
+	}
+}
+
+func (v *docValidator) requestBody(ptr string, body RequestBody) {
+	if body.Content == nil {
+		v.report(at(ptr, "content"), "the request body content is required")
+	}
+	v.content(at(ptr, "content"), body.Content)
+}
+
+func (v *docValidator) responses(ptr string, responses Responses) {
+	count := 0
+	for code, resp := range responses.All() {
+		count++
+		ptr := at(ptr, code)
+		if !IsValidStatusCode(code) {
+			v.report(ptr, "invalid response status code %q", code)
+		}
+		if v.ref(ptr, resp.Ref) {
+			v.response(ptr, resp.Value)
+		}
+	}
+	if count == 0 && strings.HasPrefix(v.doc.Version, "3.0.") {
+		v.report(ptr, "at least one response is required")
+	}
+}
+
+func (v *docValidator) response(ptr string, resp Response) {
+	if resp.Description == "" {
+		v.report(at(ptr, "description"), "the response description is required")
+	}
+	v.headers(at(ptr, "headers"), resp.Headers)
+	v.content(at(ptr, "content"), resp.Content)
+	for _, name := range sortedKeys(resp.Links) {
+		ptr := at(ptr, "links", name)
+		link := resp.Links[name]
+		if v.ref(ptr, link.Ref) {
+			v.link(ptr, link.Value)
+		}
+	}
+}
+
+func (v *docValidator) link(ptr string, link Link) {
+	switch {
+	case link.OperationRef != "" && link.OperationID != "":
+		v.report(ptr, "operationRef and operationId are mutually exclusive")
+	case link.OperationRef == "" && link.OperationID == "":
+		v.report(ptr, "either operationRef or operationId is required")
+	case link.OperationRef != "":
+		v.ref(at(ptr, "operationRef"), link.OperationRef)
+	}
+}
+
+func (v *docValidator) callback(ptr string, callback Callback) {
+	for _, expr := range sortedKeys(callback.Items) {
+		v.pathItem(at(ptr, expr), callback.Items[expr], nil)
+	}
+}
+
+func (v *docValidator) externalDoc(ptr string, doc ExternalDoc) {
+	if doc.URL == "" && (doc.Description != "" || len(doc.Extensions) > 0) {
+		v.report(at(ptr, "url"), "the external documentation URL is required")
+	}
+}
+
+func (v *docValidator) security(ptr string, reqs []SecurityRequirement) {
+	for i, req := range reqs {
+		for _, name := range sortedKeys(req) {
+			if _, ok := v.doc.Components.SecuritySchemes[name]; !ok {
+				v.report(at(ptr, strconv.Itoa(i), name), "the security scheme %q is not defined", name)
+			}
+		}
+	}
+}
+
+func (v *docValidator) securityScheme(ptr string, s SecurityScheme) {
+	switch s.Type {
+	case "":
+		v.report(at(ptr, "type"), "the security scheme type is required")
+	case SecurityTypeAPIKey:
+		if s.Name == "" {
+			v.report(at(ptr, "name"), "the API key name is required")
+		}
+		if s.In != InQuery && s.In != InHeader && s.In != InCookie {
+			v.report(at(ptr, "in"), "invalid API key location %q", s.In)
+		}
+	case SecurityTypeHTTP:
+		if s.Scheme == "" {
+			v.report(at(ptr, "scheme"), "the HTTP authentication scheme is required")
+		}
+	case SecurityTypeOAuth2:
+		flows := s.Flows
+		if isZero(flows.Implicit) && isZero(flows.Password) &&
+			isZero(flows.ClientCredentials) && isZero(flows.AuthorizationCode) {
+			v.report(at(ptr, "flows"), "at least one OAuth flow is required")
+		}
+		if f := flows.Implicit; !isZero(f) {
+			v.oauthURL(at(ptr, "flows", "implicit", "authorizationUrl"), f.AuthorizationURL, "authorization")
+		}
+		if f := flows.Password; !isZero(f) {
+			v.oauthURL(at(ptr, "flows", "password", "tokenUrl"), f.TokenURL, "token")
+		}
+		if f := flows.ClientCredentials; !isZero(f) {
+			v.oauthURL(at(ptr, "flows", "clientCredentials", "tokenUrl"), f.TokenURL, "token")
+		}
+		if f := flows.AuthorizationCode; !isZero(f) {
+			v.oauthURL(at(ptr, "flows", "authorizationCode", "authorizationUrl"), f.AuthorizationURL, "authorization")
+			v.oauthURL(at(ptr, "flows", "authorizationCode", "tokenUrl"), f.TokenURL, "token")
+		}
+	case SecurityTypeOpenIDConnect:
+		if s.OpenIDConnectURL == "" {
+			v.report(at(ptr, "openIdConnectUrl"), "the OpenID Connect discovery URL is required")
+		}
+	case SecurityTypeMutualTLS:
+	default:
+		v.report(at(ptr, "type"), "invalid security scheme type %q", s.Type)
+	}
+}
+
+// oauthURL checks that the required URL of an OAuth flow is set.
+func (v *docValidator) oauthURL(ptr, url, kind string) {
+	if url == "" {
+		v.report(ptr, "the %s URL is required", kind)
+	}
+}
+
+func (v *docValidator) components(c Components) {
+	ptr := "/components"
+	checkNames := func(kind string, names []string) {
+		for _, name := range names {
+			if !componentNameRe.MatchString(name) {
+				v.report(at(ptr, kind, name), "invalid component name %q", name)
+			}
+		}
+	}
+	checkNames("schemas", sortedKeys(c.Schemas))
+	for _, name := range sortedKeys(c.Schemas) {
+		v.schema(at(ptr, "schemas", name), c.Schemas[name])
+	}
+	checkNames("responses", sortedKeys(c.Responses))
+	for _, name := range sortedKeys(c.Responses) {
+		ptr := at(ptr, "responses", name)
+		if resp := c.Responses[name]; v.ref(ptr, resp.Ref) {
+			v.response(ptr, resp.Value)
+		}
+	}
+	checkNames("parameters", sortedKeys(c.Parameters))
+	for _, name := range sortedKeys(c.Parameters) {
+		ptr := at(ptr, "parameters", name)
+		if p := c.Parameters[name]; v.ref(ptr, p.Ref) {
+			v.parameter(ptr, p.Value)
+		}
+	}
+	checkNames("examples", sortedKeys(c.Examples))
+	for _, name := range sortedKeys(c.Examples) {
+		ptr := at(ptr, "examples", name)
+		if ex := c.Examples[name]; v.ref(ptr, ex.Ref) {
+			v.example(ptr, ex.Value)
+		}
+	}
+	checkNames("requestBodies", sortedKeys(c.RequestBodies))
+	for _, name := range sortedKeys(c.RequestBodies) {
+		ptr := at(ptr, "requestBodies", name)
+		if body := c.RequestBodies[name]; v.ref(ptr, body.Ref) {
+			v.requestBody(ptr, body.Value)
+		}
+	}
+	checkNames("headers", sortedKeys(c.Headers))
+	v.headers(at(ptr, "headers"), c.Headers)
+	checkNames("securitySchemes", sortedKeys(c.SecuritySchemes))
+	for _, name := range sortedKeys(c.SecuritySchemes) {
+		ptr := at(ptr, "securitySchemes", name)
+		if s := c.SecuritySchemes[name]; v.ref(ptr, s.Ref) {
+			v.securityScheme(ptr, s.Value)
+		}
+	}
+	checkNames("links", sortedKeys(c.Links))
+	for _, name := range sortedKeys(c.Links) {
+		ptr := at(ptr, "links", name)
+		if link := c.Links[name]; v.ref(ptr, link.Ref) {
+			v.link(ptr, link.Value)
+		}
+	}
+	checkNames("callbacks", sortedKeys(c.Callbacks))
+	for _, name := range sortedKeys(c.Callbacks) {
+		ptr := at(ptr, "callbacks", name)
+		if callback := c.Callbacks[name]; v.ref(ptr, callback.Ref) {
+			v.callback(ptr, callback.Value)
+		}
+	}
+	checkNames("pathItems", sortedKeys(c.PathItems))
+	for _, name := range sortedKeys(c.PathItems) {
+		v.pathItem(at(ptr, "pathItems", name), c.PathItems[name], nil)
+	}
+}
+
+// schema checks the references in the schema and all its subschemas.
+func (v *docValidator) schema(ptr string, s *Schema) {
+	if s == nil {
+		return
+	}
+	for sub, ptr := range s.subschemas(ptr) {
+		if sub.ID != "" {
+			// References inside of a separate schema resource are relative to it.
+			continue
+		}
+		v.ref(at(ptr, "$ref"), sub.Ref)
+	}
+}
+
+// subschemas iterates over the schema and all its subschemas, recursively, together with their JSON Pointers.
+//
+// The traversal stops at subschemas with $id since they are separate schema resources.
+func (s *Schema) subschemas(ptr string) func(yield func(*Schema, string) bool) {
+	return func(yield func(*Schema, string) bool) {
+		s.walk(ptr, yield)
+	}
+}
+
+func (s *Schema) walk(ptr string, yield func(*Schema, string) bool) bool {
+	if s == nil {
+		return true
+	}
+	if !yield(s, ptr) {
+		return false
+	}
+	single := []struct {
+		key    string
+		schema *Schema
+	}{
+		{"not", s.Not}, {"if", s.If}, {"then", s.Then}, {"else", s.Else},
+		{"items", s.Items}, {"contains", s.Contains},
+		{"additionalProperties", s.AdditionalProperties}, {"propertyNames", s.PropertyNames},
+		{"unevaluatedItems", s.UnevaluatedItems}, {"unevaluatedProperties", s.UnevaluatedProperties},
+		{"contentSchema", s.ContentSchema},
+	}
+	for _, sub := range single {
+		if sub.schema != nil && sub.schema.ID == "" && !sub.schema.walk(at(ptr, sub.key), yield) {
+			return false
+		}
+	}
+	lists := []struct {
+		key     string
+		schemas []*Schema
+	}{
+		{"allOf", s.AllOf}, {"anyOf", s.AnyOf}, {"oneOf", s.OneOf}, {"prefixItems", s.PrefixItems},
+	}
+	for _, list := range lists {
+		for i, sub := range list.schemas {
+			if sub != nil && sub.ID == "" && !sub.walk(at(ptr, list.key, strconv.Itoa(i)), yield) {
+				return false
+			}
+		}
+	}
+	dicts := []struct {
+		key     string
+		schemas map[string]*Schema
+	}{
+		{"$defs", s.Defs}, {"properties", s.Properties},
+		{"patternProperties", s.PatternProperties}, {"dependentSchemas", s.DependentSchemas},
+	}
+	for _, dict := range dicts {
+		for _, name := range sortedKeys(dict.schemas) {
+			sub := dict.schemas[name]
+			if sub != nil && sub.ID == "" && !sub.walk(at(ptr, dict.key, name), yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ref reports the reference if it's a local one and points to nothing.
+// It returns true if the object isn't a reference, so that its value should be validated.
+func (v *docValidator) ref(ptr, ref string) bool {
+	if ref == "" {
+		return true
+	}
+	fragment, ok := strings.CutPrefix(ref, "#")
+	if !ok || (fragment != "" && !strings.HasPrefix(fragment, "/")) {
+		// External references and anchors aren't checked.
+		return false
+	}
+	if v.raw == nil {
+		return false
+	}
+	if _, ok := resolvePointer(v.raw, fragment, nil); !ok {
+		v.report(ptr, "unresolved reference %q", ref)
+	}
+	return false
+}
+
+// resolvePointer finds the value in the generic JSON value by the JSON Pointer,
+// which may be percent-encoded as a URI fragment.
+//
+// If visit isn't nil, it's called for every value on the way, excluding the found one.
+func resolvePointer(root any, ptr string, visit func(node any)) (any, bool) {
+	if ptr == "" {
+		return root, true
+	}
+	current := root
+	for _, token := range strings.Split(ptr[1:], "/") {
+		if visit != nil {
+			visit(current)
+		}
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = unescapePointer(token)
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,253 @@
+package openapi_test
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestValidate(t *testing.T) {
+	data, err := os.ReadFile("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_Violations(t *testing.T) {
+	idParam := openapi.Inline(openapi.Parameter{
+		Name:   "id",
+		In:     "path",
+		Schema: &openapi.Schema{Type: openapi.Types{openapi.TypeString}},
+	})
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info: openapi.Info{
+			Version: "1.0",
+			License: openapi.License{Name: "MIT", Identifier: "MIT", URL: "https://opensource.org/licenses/MIT"},
+		},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/users/{userId}": openapi.PathItem{
+				Get: openapi.Operation{
+					OperationID: "getUser",
+					Parameters: []openapi.RefOr[openapi.Parameter]{
+						idParam,
+						idParam,
+						openapi.Ref[openapi.Parameter]("#/components/parameters/Missing"),
+					},
+					Responses: openapi.Responses{
+						OK: openapi.Inline(openapi.Response{Headers: map[string]openapi.RefOr[openapi.Header]{
+							"ETag": openapi.Inline(openapi.Header{Schema: &openapi.Schema{}}),
+						}}),
+					},
+				},
+			},
+			"/users": openapi.PathItem{
+				Get: openapi.Operation{
+					OperationID: "getUser",
+					Responses: openapi.Responses{
+						OK: openapi.Inline(openapi.Response{
+							Description: "The users",
+							Content: map[string]openapi.MediaType{
+								"application/json": {
+									Schema: &openapi.Schema{Items: &openapi.Schema{Ref: "#/components/schemas/User"}},
+								},
+							},
+						}),
+					},
+				},
+			},
+		}},
+		Components: openapi.Components{
+			Examples: map[string]openapi.RefOr[openapi.Example]{
+				"User": openapi.Inline(openapi.Example{Value: "alice", ExternalValue: "https://example.com/alice.json"}),
+			},
+		},
+	}
+	err := doc.Validate()
+	var errs openapi.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := []openapi.ValidationError{
+		{Pointer: "/info/title", Message: "the API title is required"},
+		{Pointer: "/info/license", Message: "identifier and url are mutually exclusive"},
+		{Pointer: "/paths/~1users/get/responses/200/content/application~1json/schema/items/$ref", Message: `unresolved reference "#/components/schemas/User"`},
+		{Pointer: "/paths/~1users~1{userId}/get/operationId", Message: `duplicate operation ID "getUser", also used by /paths/~1users/get`},
+		{Pointer: "/paths/~1users~1{userId}/get/parameters/0/required", Message: "path parameters must be required"},
+		{Pointer: "/paths/~1users~1{userId}/get/parameters/1/required", Message: "path parameters must be required"},
+		{Pointer: "/paths/~1users~1{userId}/get/parameters/1", Message: `duplicate parameter "id" in path`},
+		{Pointer: "/paths/~1users~1{userId}/get/parameters/2", Message: `unresolved reference "#/components/parameters/Missing"`},
+		{Pointer: "/paths/~1users~1{userId}/get", Message: `the path parameter "id" is not in the path template`},
+		{Pointer: "/paths/~1users~1{userId}/get", Message: `the path template expression "userId" has no matching path parameter`},
+		{Pointer: "/paths/~1users~1{userId}/get/responses/200/description", Message: "the response description is required"},
+		{Pointer: "/components/examples/User", Message: "value and externalValue are mutually exclusive"},
+	}
+	if !slices.Equal(errs, want) {
+		t.Errorf("unexpected violations:\n%v", errs)
+	}
+}
+
+func TestValidate_Unencodable(t *testing.T) {
+	doc := openapi.OpenAPI{Extensions: map[string]any{"bad": 1}}
+	err := doc.Validate()
+	var errs openapi.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	var ptrs []string
+	for _, e := range errs {
+		ptrs = append(ptrs, e.Pointer)
+	}
+	want := []string{"", "/openapi", "/info/title", "/info/version"}
+	if !slices.Equal(ptrs, want) {
+		t.Errorf("unexpected violations:\n%v", errs)
+	}
+}
+
+func TestValidate_InvalidEnums(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Components: openapi.Components{
+			Parameters: map[string]openapi.RefOr[openapi.Parameter]{
+				"limit": openapi.Inline(openapi.Parameter{Name: "limit", In: "body", Style: openapi.StyleForm, Schema: &openapi.Schema{}}),
+			},
+			SecuritySchemes: map[string]openapi.RefOr[openapi.SecurityScheme]{
+				"basic": openapi.Inline(openapi.SecurityScheme{Type: "basic"}),
+			},
+		},
+	}
+	err := doc.Validate()
+	var errs openapi.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if len(errs) != 3 || errs[0].Pointer != "" {
+		t.Fatalf("unexpected violations:\n%v", errs)
+	}
+	want := []openapi.ValidationError{
+		{Pointer: "/components/parameters/limit/in", Message: `invalid parameter location "body"`},
+		{Pointer: "/components/securitySchemes/basic/type", Message: `invalid security scheme type "basic"`},
+	}
+	if !slices.Equal(errs[1:], want) {
+		t.Errorf("unexpected violations:\n%v", errs)
+	}
+}
+
+func TestValidate_EquivalentPaths(t *testing.T) {
+	param := func(name string) []openapi.RefOr[openapi.Parameter] {
+		return []openapi.RefOr[openapi.Parameter]{openapi.Inline(openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: ptr(true),
+			Schema:   &openapi.Schema{},
+		})}
+	}
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets/{id}":   openapi.PathItem{Parameters: param("id")},
+			"/pets/{name}": openapi.PathItem{Parameters: param("name")},
+		}},
+	}
+	err := doc.Validate()
+	want := `/paths/~1pets~1{name}: the path is equivalent to "/pets/{id}"`
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ReferencedParameters(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets/{id}": openapi.PathItem{
+				Get: openapi.Operation{
+					Parameters: []openapi.RefOr[openapi.Parameter]{
+						openapi.Ref[openapi.Parameter]("#/components/parameters/PetID"),
+						openapi.Ref[openapi.Parameter]("#/components/parameters/Limit"),
+						openapi.Inline(openapi.Parameter{Name: "limit", In: openapi.InQuery, Schema: &openapi.Schema{}}),
+					},
+					Responses: openapi.Responses{
+						NoContent: openapi.Inline(openapi.Response{Description: "The pet"}),
+					},
+				},
+			},
+		}},
+		Components: openapi.Components{
+			Parameters: map[string]openapi.RefOr[openapi.Parameter]{
+				"PetID": openapi.Inline(openapi.Parameter{Name: "id", In: openapi.InPath, Required: ptr(true), Schema: &openapi.Schema{}}),
+				"Limit": openapi.Inline(openapi.Parameter{Name: "limit", In: openapi.InQuery, Schema: &openapi.Schema{}}),
+			},
+		},
+	}
+	err := doc.Validate()
+	want := `/paths/~1pets~1{id}/get/parameters/2: duplicate parameter "limit" in query`
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_Styles(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0"},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/pets": openapi.PathItem{
+				Post: openapi.Operation{
+					Parameters: []openapi.RefOr[openapi.Parameter]{
+						openapi.Inline(openapi.Parameter{Name: "filter", In: openapi.InQuery, Style: openapi.StyleDeepObject, Schema: &openapi.Schema{}}),
+						openapi.Inline(openapi.Parameter{Name: "X-Filter", In: openapi.InHeader, Style: openapi.StyleDeepObject, AllowEmptyValue: ptr(false), Schema: &openapi.Schema{}}),
+						openapi.Inline(openapi.Parameter{Name: "sort", In: openapi.InCookie, Style: openapi.StyleMatrix, AllowEmptyValue: ptr(true), Schema: &openapi.Schema{}}),
+					},
+					RequestBody: openapi.Inline(openapi.RequestBody{
+						Content: map[string]openapi.MediaType{
+							"application/x-www-form-urlencoded": {
+								Encoding: map[string]openapi.Encoding{
+									"tags": {Style: openapi.StylePipeDelimited},
+									"name": {Style: openapi.StyleLabel},
+								},
+							},
+						},
+					}),
+					Responses: openapi.Responses{
+						NoContent: openapi.Inline(openapi.Response{
+							Description: "Created",
+							Headers: map[string]openapi.RefOr[openapi.Header]{
+								"Location": openapi.Inline(openapi.Header{Style: openapi.StyleForm, Schema: &openapi.Schema{}}),
+							},
+						}),
+					},
+				},
+			},
+		}},
+	}
+	err := doc.Validate()
+	var errs openapi.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := []openapi.ValidationError{
+		{Pointer: "/paths/~1pets/post/parameters/1/style", Message: `the style "deepObject" is not allowed in header`},
+		{Pointer: "/paths/~1pets/post/parameters/2/style", Message: `the style "matrix" is not allowed in cookie`},
+		{Pointer: "/paths/~1pets/post/parameters/2/allowEmptyValue", Message: "allowEmptyValue is valid only for query parameters"},
+		{Pointer: "/paths/~1pets/post/requestBody/content/application~1x-www-form-urlencoded/encoding/name/style", Message: `the style "label" is not allowed in encodings`},
+		{Pointer: "/paths/~1pets/post/responses/204/headers/Location/style", Message: `the style "form" is not allowed in headers`},
+	}
+	if !slices.Equal(errs, want) {
+		t.Errorf("unexpected violations:\n%v", errs)
+	}
+}
@@ -0,0 +1,295 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteYAML encodes the document as YAML.
+//
+// The output has the same fields and omission rules as the JSON encoding.
+// Fields of objects are written in the order of the specification
+// and keys of maps, like paths and response codes, are sorted.
+func WriteYAML(w io.Writer, doc *OpenAPI) error {
+	node, err := doc.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	err = enc.Encode(node)
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// ReadYAML decodes an OpenAPI document from YAML.
+//
+// The same as [Parse] but for YAML. Since JSON is a subset of YAML, it also accepts JSON.
+func ReadYAML(r io.Reader) (*OpenAPI, error) {
+	var node yaml.Node
+	err := yaml.NewDecoder(r).Decode(&node)
+	if err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	data, err := yamlToJSON(&node)
+	if err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	return Parse(data)
+}
+
+// MarshalYAML implements [yaml.Marshaler].
+func (o OpenAPI) MarshalYAML() (any, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return jsonToYAML(dec)
+}
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (o *OpenAPI) UnmarshalYAML(node *yaml.Node) error {
+	data, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(data, o)
+}
+
+// jsonToYAML reads the next JSON value from the decoder and converts it into a YAML node.
+func jsonToYAML(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch val := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if val == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			item, err := jsonToYAML(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		// Consume the closing delimiter.
+		_, err = dec.Token()
+		return node, err
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: val}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(val.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: val.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(val)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// The number of nodes that aliases may expand a YAML document to,
+// relative to the number of nodes in the document itself.
+const yamlAliasRatio = 10
+
+// The minimal number of nodes that aliases may expand a YAML document to.
+const minYAMLExpansion = 10_000
+
+// yamlToJSON converts the YAML node into JSON.
+//
+// Keys of mappings are always converted into strings, so that response codes
+// like 200 are handled the same way as "200". Scalars keep their YAML types.
+//
+// Aliases and merge keys are expanded. If they expand the document to many more nodes
+// than it has, like in the "billion laughs" attack, an error is returned.
+func yamlToJSON(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	budget := max(countYAMLNodes(node)*yamlAliasRatio, minYAMLExpansion)
+	err := writeYAMLNode(&buf, node, &budget)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// countYAMLNodes returns the number of nodes in the tree without expanding aliases.
+func countYAMLNodes(node *yaml.Node) int {
+	count := 1
+	for _, child := range node.Content {
+		count += countYAMLNodes(child)
+	}
+	return count
+}
+
+// writeYAMLNode writes the node as JSON.
+//
+// The budget is the number of nodes that can still be written. It's decreased by each written node.
+func writeYAMLNode(buf *bytes.Buffer, node *yaml.Node, budget *int) error {
+	*budget--
+	if *budget < 0 {
+		return fmt.Errorf("line %d: aliases expand the document too much", node.Line)
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return errors.New("empty document")
+		}
+		return writeYAMLNode(buf, node.Content[0], budget)
+	case yaml.AliasNode:
+		return writeYAMLNode(buf, node.Alias, budget)
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := writeYAMLNode(buf, item, budget)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		first := true
+		err := writeYAMLPairs(buf, node, &first, budget)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.ScalarNode:
+		return writeYAMLScalar(buf, node)
+	}
+	return fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// writeYAMLPairs writes the key-value pairs of the mapping, including the merged ones.
+//
+// The pairs from merge keys ("<<") are written first, so that the explicit keys override them.
+func writeYAMLPairs(buf *bytes.Buffer, node *yaml.Node, first *bool, budget *int) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		if key.Tag != "!!merge" {
+			continue
+		}
+		if val.Kind == yaml.AliasNode {
+			val = val.Alias
+		}
+		sources := []*yaml.Node{val}
+		if val.Kind == yaml.SequenceNode {
+			sources = val.Content
+		}
+		for _, src := range sources {
+			if src.Kind == yaml.AliasNode {
+				src = src.Alias
+			}
+			if src.Kind != yaml.MappingNode {
+				return fmt.Errorf("line %d: merge key value must be a mapping", val.Line)
+			}
+			*budget--
+			if *budget < 0 {
+				return fmt.Errorf("line %d: aliases expand the document too much", val.Line)
+			}
+			err := writeYAMLPairs(buf, src, first, budget)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			continue
+		}
+		if key.Kind == yaml.AliasNode {
+			key = key.Alias
+		}
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: mapping key must be a scalar", key.Line)
+		}
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+		rawKey, err := json.Marshal(key.Value)
+		if err != nil {
+			return err
+		}
+		buf.Write(rawKey)
+		buf.WriteByte(':')
+		err = writeYAMLNode(buf, val, budget)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeYAMLScalar(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		var val bool
+		err := node.Decode(&val)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(buf, val)
+		return nil
+	case "!!int", "!!float":
+		// Keep the number exactly as written if JSON allows it.
+		if json.Valid([]byte(node.Value)) {
+			buf.WriteString(node.Value)
+			return nil
+		}
+		var val any
+		err := node.Decode(&val)
+		if err != nil {
+			return err
+		}
+		if f, ok := val.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return fmt.Errorf("line %d: %s cannot be represented in JSON", node.Line, node.Value)
+		}
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		buf.Write(raw)
+		return nil
+	}
+	// Strings and other scalars, like timestamps, are kept as strings.
+	raw, err := json.Marshal(node.Value)
+	if err != nil {
+		return err
+	}
+	buf.Write(raw)
+	return nil
+}
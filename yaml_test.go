@@ -0,0 +1,160 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/openapi"
+)
+
+func TestWriteYAML(t *testing.T) {
+	doc := openapi.OpenAPI{
+		Version: "3.1.0",
+		Info:    openapi.Info{Title: "Cool service", Version: "1.0"},
+		Paths: openapi.Paths{Items: map[string]openapi.PathItem{
+			"/echo": openapi.PathItem{
+				Post: openapi.Operation{
+					Summary: "Scream into the void",
+					Responses: openapi.Responses{
+						OK: openapi.Inline(openapi.Response{
+							Description: "Echo",
+							Content: map[string]openapi.MediaType{
+								"text/plain": {
+									Examples: map[string]openapi.RefOr[openapi.Example]{
+										"number": openapi.Inline(openapi.Example{Value: "123"}),
+									},
+								},
+							},
+						}),
+					},
+				},
+			},
+			"/about": openapi.PathItem{},
+		}},
+	}
+	var buf bytes.Buffer
+	err := openapi.WriteYAML(&buf, &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimLeft(`
+openapi: 3.1.0
+info:
+  title: Cool service
+  version: "1.0"
+paths:
+  /about: {}
+  /echo:
+    post:
+      summary: Scream into the void
+      responses:
+        "200":
+          description: Echo
+          content:
+            text/plain:
+              examples:
+                number:
+                  value: "123"
+`, "\n")
+	if buf.String() != want {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+}
+
+func TestReadYAML(t *testing.T) {
+	input := `
+openapi: 3.0.3
+info:
+  title: Cool service
+  version: "1.0"
+paths:
+  /echo:
+    post:
+      responses:
+        200: &ok
+          description: OK
+          content:
+            application/json:
+              example:
+                count: 3
+                ratio: 0.50
+                flag: yes
+                released: 2024-01-02
+                name: "42"
+        201:
+          <<: *ok
+          description: Created
+`
+	doc, err := openapi.ReadYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	example := `{"count": 3, "ratio": 0.50, "flag": "yes", "released": "2024-01-02", "name": "42"}`
+	want := `{
+		"openapi": "3.0.3",
+		"info": {"title": "Cool service", "version": "1.0"},
+		"paths": {"/echo": {"post": {"responses": {
+			"200": {"description": "OK", "content": {"application/json": {"example": ` + example + `}}},
+			"201": {"description": "Created", "content": {"application/json": {"example": ` + example + `}}}
+		}}}}
+	}`
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Errorf("unexpected document: %s", got)
+	}
+}
+
+func TestReadYAML_BillionLaughs(t *testing.T) {
+	input := `
+openapi: 3.0.3
+info:
+  title: Cool service
+  version: "1.0"
+x-a: &a ["lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol"]
+x-b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a]
+x-c: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b]
+x-d: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c]
+x-e: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d]
+x-f: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e]
+x-g: &g [*f, *f, *f, *f, *f, *f, *f, *f, *f]
+x-h: &h [*g, *g, *g, *g, *g, *g, *g, *g, *g]
+x-i: &i [*h, *h, *h, *h, *h, *h, *h, *h, *h]
+`
+	_, err := openapi.ReadYAML(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), "aliases expand the document too much") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestYAML_RoundTrip(t *testing.T) {
+	input, err := os.ReadFile("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = openapi.WriteYAML(&buf, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := openapi.ReadYAML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, output, input) {
+		t.Errorf("round trip changed the document: %s", output)
+	}
+}